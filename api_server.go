@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	apiReadHeaderTimeout = 5 * time.Second
+	apiDateLayout        = "2006-01-02"
+)
+
+var errNotLoopback = errors.New("api address must be a loopback address")
+
+// APIServer exposes timer, tasks and pomodoros over local HTTP.
+type APIServer struct {
+	logger       *slog.Logger
+	stateManager *StateManager
+	taskTracker  taskManager
+	pomodoros    pomodoroTracker
+	events       *EventBroker
+	server       *http.Server
+}
+
+type timerStatus struct {
+	Timer          string `json:"timer"`
+	State          string `json:"state"`
+	SecondsLeft    int    `json:"seconds_left"`
+	SecondsElapsed int    `json:"seconds_elapsed,omitempty"`
+}
+
+type timerRequest struct {
+	Timer string `json:"timer"`
+	State string `json:"state"`
+}
+
+type taskRequest struct {
+	Name              string `json:"name"`
+	PomodorosRequired int    `json:"pomodoros_required"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+func NewAPIServer(l *slog.Logger, addr string, sm *StateManager, tt taskManager, pt pomodoroTracker,
+	events *EventBroker) *APIServer {
+	s := &APIServer{
+		logger:       l,
+		stateManager: sm,
+		taskTracker:  tt,
+		pomodoros:    pt,
+		events:       events,
+		server:       nil,
+	}
+	s.server = &http.Server{
+		Addr:              addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: apiReadHeaderTimeout,
+	}
+	return s
+}
+
+// Run starts listening, it's blocking operation.
+func (s *APIServer) Run() error {
+	host, _, err := net.SplitHostPort(s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("can't parse api address: %w", err)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("%w: %s", errNotLoopback, host)
+	}
+
+	s.logger.Info("starting api server", slog.String("address", s.server.Addr))
+	if err = s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("api server stopped: %w", err)
+	}
+	return nil
+}
+
+func (s *APIServer) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /timer", s.handleGetTimer)
+	mux.HandleFunc("POST /timer", s.handleSetTimer)
+	mux.HandleFunc("GET /tasks", s.handleGetTasks)
+	mux.HandleFunc("POST /tasks", s.handleCreateTask)
+	mux.HandleFunc("GET /pomodoros", s.handleGetPomodoros)
+	mux.HandleFunc("GET /events", s.handleEvents)
+
+	return mux
+}
+
+func (s *APIServer) currentStatus() timerStatus {
+	timerType := s.stateManager.CurrentTimer()
+	return s.status(timerType, s.stateManager.CurrentState())
+}
+
+func (s *APIServer) status(timerType TimerType, state TimerState) timerStatus {
+	return timerStatus{
+		Timer:          timerType.String(),
+		State:          state.String(),
+		SecondsLeft:    int(s.stateManager.timeToFinish(timerType).Seconds()),
+		SecondsElapsed: int(s.stateManager.elapsed(timerType).Seconds()),
+	}
+}
+
+func (s *APIServer) handleGetTimer(w http.ResponseWriter, _ *http.Request) {
+	s.writeJSON(w, http.StatusOK, s.currentStatus())
+}
+
+func (s *APIServer) handleSetTimer(w http.ResponseWriter, r *http.Request) {
+	var req timerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+
+	state, ok := parseTimerState(req.State)
+	if !ok {
+		s.writeError(w, http.StatusBadRequest, "state must be one of: active, paused, finished")
+		return
+	}
+
+	timerType := s.stateManager.CurrentTimer()
+	if req.Timer != "" {
+		timerType, ok = parseTimerType(req.Timer)
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, "timer must be one of: focus, break, stopwatch")
+			return
+		}
+	}
+
+	curState, curTimer := s.stateManager.CurrentState(), s.stateManager.CurrentTimer()
+	switch {
+	case state == StateFinished && curState != StateActive:
+		s.writeError(w, http.StatusConflict, "only running timer can be finished")
+		return
+	case state == StatePaused && (curState != StateActive || curTimer != timerType):
+		s.writeError(w, http.StatusConflict, "only running timer can be paused")
+		return
+	}
+
+	if err := s.stateManager.SetState(state, timerType); err != nil {
+		s.writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	s.writeJSON(w, http.StatusOK, s.currentStatus())
+}
+
+func (s *APIServer) handleGetTasks(w http.ResponseWriter, _ *http.Request) {
+	tasks, err := s.taskTracker.Tasks()
+	if err != nil {
+		s.logger.Error("api: can't get tasks", slog.Any("error", err))
+		s.writeError(w, http.StatusInternalServerError, "can't get tasks")
+		return
+	}
+	if tasks == nil {
+		tasks = []*Task{}
+	}
+	s.writeJSON(w, http.StatusOK, tasks)
+}
+
+func (s *APIServer) handleCreateTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.writeError(w, http.StatusBadRequest, "invalid json body")
+		return
+	}
+	if req.Name == "" {
+		s.writeError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	tasks, err := s.taskTracker.Tasks()
+	if err != nil {
+		s.logger.Error("api: can't get tasks", slog.Any("error", err))
+		s.writeError(w, http.StatusInternalServerError, "can't create task")
+		return
+	}
+
+	task := &Task{
+		ID:                 -1,
+		Name:               req.Name,
+		PomodorosRequired:  max(req.PomodorosRequired, 0),
+		PomodorosCompleted: 0,
+		IsComplete:         req.PomodorosRequired <= 0,
+		IsActive:           len(tasks) == 0,
+		CreateAt:           time.Now(),
+		UUID:               "",
+		CompleteAt:         time.Time{},
+		line:               "",
+	}
+
+	if err = s.taskTracker.CreateTask(task); err != nil {
+		s.logger.Error("api: can't create task", slog.Any("error", err))
+		s.writeError(w, http.StatusInternalServerError, "can't create task")
+		return
+	}
+	s.writeJSON(w, http.StatusCreated, task)
+}
+
+// handleGetPomodoros supports optional `from` and `to` (inclusive) query params in YYYY-MM-DD format
+// and `profile` param to get sessions of one timer profile.
+func (s *APIServer) handleGetPomodoros(w http.ResponseWriter, r *http.Request) {
+	fromStr, toStr := r.URL.Query().Get("from"), r.URL.Query().Get("to")
+
+	var (
+		pomodoros []*Pomodoro
+		err       error
+	)
+	if fromStr == "" && toStr == "" {
+		pomodoros, err = s.pomodoros.Pomodoros()
+	} else {
+		from, to, parseErr := parseDateRange(fromStr, toStr)
+		if parseErr != nil {
+			s.writeError(w, http.StatusBadRequest, parseErr.Error())
+			return
+		}
+		pomodoros, err = s.pomodoros.PomodorosBetween(from, to)
+	}
+	if err != nil {
+		s.logger.Error("api: can't get pomodoros", slog.Any("error", err))
+		s.writeError(w, http.StatusInternalServerError, "can't get pomodoros")
+		return
+	}
+	if profile := r.URL.Query().Get("profile"); profile != "" {
+		pomodoros = filterByProfile(pomodoros, profile)
+	}
+	if pomodoros == nil {
+		pomodoros = []*Pomodoro{}
+	}
+	s.writeJSON(w, http.StatusOK, pomodoros)
+}
+
+func filterByProfile(pomodoros []*Pomodoro, profile string) []*Pomodoro {
+	var filtered []*Pomodoro
+	for _, pomodoro := range pomodoros {
+		if pomodoro.Profile == profile {
+			filtered = append(filtered, pomodoro)
+		}
+	}
+	return filtered
+}
+
+// handleEvents streams state changes and every second ticks of running timer as server-sent events.
+func (s *APIServer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		s.writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	events, unsubscribe := s.events.Subscribe()
+	defer unsubscribe()
+
+	tick := time.NewTicker(screenRefreshInterval)
+	defer tick.Stop()
+
+	s.writeEvent(w, "state", s.currentStatus())
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			s.writeEvent(w, "state", s.status(event.TimerType, event.NewState))
+		case <-tick.C:
+			if s.stateManager.CurrentState() != StateActive {
+				continue
+			}
+			s.writeEvent(w, "tick", s.currentStatus())
+		}
+		flusher.Flush()
+	}
+}
+
+func (s *APIServer) writeEvent(w http.ResponseWriter, name string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.logger.Error("api: can't marshal event", slog.Any("error", err))
+		return
+	}
+	if _, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		s.logger.Debug("api: can't write event", slog.Any("error", err))
+	}
+}
+
+func (s *APIServer) writeJSON(w http.ResponseWriter, code int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(data); err != nil {
+		s.logger.Error("api: can't write response", slog.Any("error", err))
+	}
+}
+
+func (s *APIServer) writeError(w http.ResponseWriter, code int, msg string) {
+	s.writeJSON(w, code, apiError{Error: msg})
+}
+
+func parseTimerState(str string) (TimerState, bool) {
+	for _, state := range []TimerState{StatePaused, StateActive, StateFinished} {
+		if state.String() == str {
+			return state, true
+		}
+	}
+	return StatePaused, false
+}
+
+func parseTimerType(str string) (TimerType, bool) {
+	for _, timerType := range []TimerType{FocusTimer, BreakTimer, StopwatchTimer} {
+		if timerType.String() == str {
+			return timerType, true
+		}
+	}
+	return FocusTimer, false
+}
+
+// parseDateRange converts inclusive dates to [from, to) interval in local time zone.
+func parseDateRange(fromStr, toStr string) (time.Time, time.Time, error) {
+	from := time.Time{}
+	to := time.Now().AddDate(100, 0, 0)
+
+	if fromStr != "" {
+		date, err := time.ParseInLocation(apiDateLayout, fromStr, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid 'from' date, expected %s", apiDateLayout)
+		}
+		from = date
+	}
+	if toStr != "" {
+		date, err := time.ParseInLocation(apiDateLayout, toStr, time.Local)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid 'to' date, expected %s", apiDateLayout)
+		}
+		to = date.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
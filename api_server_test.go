@@ -0,0 +1,155 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAPIServer(stateChan chan StateEvent) *APIServer {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration}, s)
+	pomodoroManager := NewPomodoroManager(slog.Default(), s, nil)
+
+	return NewAPIServer(slog.Default(), "127.0.0.1:0", stateManager, s, pomodoroManager, NewEventBroker())
+}
+
+func TestAPIServer_GetTimer(t *testing.T) {
+	server := newTestAPIServer(make(chan StateEvent))
+
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/timer", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var status timerStatus
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+	assert.Equal(t, "focus", status.Timer)
+	assert.Equal(t, "paused", status.State)
+	assert.Equal(t, int(focusDuration.Seconds()), status.SecondsLeft)
+}
+
+func TestAPIServer_SetTimer(t *testing.T) {
+	stateChan := make(chan StateEvent, 1)
+	server := newTestAPIServer(stateChan)
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"state": "finished", "timer": "focus"}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/timer", body))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	body = strings.NewReader(`{"state": "paused", "timer": "focus"}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/timer", body))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = httptest.NewRecorder()
+	body = strings.NewReader(`{"state": "active", "timer": "break"}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/timer", body))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	event := <-stateChan
+	assert.Equal(t, StateActive, event.NewState)
+	assert.Equal(t, BreakTimer, event.TimerType)
+	assert.Equal(t, StateActive, server.stateManager.CurrentState())
+
+	// focus isn't running, only break can be paused
+	rec = httptest.NewRecorder()
+	body = strings.NewReader(`{"state": "paused", "timer": "focus"}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/timer", body))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	server.stateManager.breakTimer.Stop()
+}
+
+func TestAPIServer_SetTimerStrict(t *testing.T) {
+	stateChan := make(chan StateEvent, 1)
+	server := newTestAPIServer(stateChan)
+	server.stateManager.UpdateTimerConfig(config.TimerConfig{FocusDuration: focusDuration,
+		BreakDuration: breakDuration, Strict: true})
+	require.NoError(t, server.stateManager.SetState(StateActive, FocusTimer))
+	<-stateChan
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"state": "active", "timer": "break"}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/timer", body))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Contains(t, rec.Body.String(), "focus is running")
+
+	server.stateManager.focusTimer.Stop()
+}
+
+func TestAPIServer_CreateTask(t *testing.T) {
+	server := newTestAPIServer(make(chan StateEvent))
+
+	rec := httptest.NewRecorder()
+	body := strings.NewReader(`{"name": "write api", "pomodoros_required": 3}`)
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tasks", body))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var tasks []*Task
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "write api", tasks[0].Name)
+	assert.Equal(t, 3, tasks[0].PomodorosRequired)
+	assert.True(t, tasks[0].IsActive)
+
+	clearTable()
+}
+
+func TestAPIServer_GetPomodoros(t *testing.T) {
+	server := newTestAPIServer(make(chan StateEvent))
+
+	day := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)
+	for i := range 3 {
+		require.NoError(t, s.CreatePomodoro(&Pomodoro{StartAt: day.AddDate(0, 0, i), FinishAt: day.AddDate(0, 0, i)}))
+	}
+
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pomodoros?from=2024-10-08&to=2024-10-09", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var pomodoros []*Pomodoro
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&pomodoros))
+	assert.Len(t, pomodoros, 2)
+
+	rec = httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pomodoros?from=07-10-2024", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	clearTable()
+}
+
+func TestAPIServer_GetPomodorosByProfile(t *testing.T) {
+	server := newTestAPIServer(make(chan StateEvent))
+
+	for _, profile := range []string{"deep work", "default", "deep work"} {
+		require.NoError(t, s.CreatePomodoro(&Pomodoro{StartAt: time.Now(), FinishAt: time.Now(), Profile: profile}))
+	}
+
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pomodoros?profile=deep+work", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var pomodoros []*Pomodoro
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&pomodoros))
+	require.Len(t, pomodoros, 2)
+	assert.Equal(t, "deep work", pomodoros[0].Profile)
+
+	clearTable()
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"embed"
 	"log/slog"
+	"os"
 
 	"github.com/arevbond/PomoTrack/config"
 )
@@ -10,13 +11,17 @@ import (
 //go:embed sounds
 var f embed.FS
 
+const databaseName = ".pomotrack.UserSessions.db"
+
 type Application struct {
 	uiManager *UIManager
+	apiServer *APIServer
+	metrics   *Metrics
 	logger    *slog.Logger
 }
 
-func NewApplication(logger *slog.Logger, cfg *config.Config) *Application {
-	database, err := NewStorage(".pomotrack.UserSessions.db", logger)
+func NewApplication(logger *slog.Logger, cfg *config.Config, keys *KeyBindings, theme *Theme) *Application {
+	database, err := NewStorage(databaseName, logger)
 	if err != nil {
 		panic(err)
 	}
@@ -27,10 +32,38 @@ func NewApplication(logger *slog.Logger, cfg *config.Config) *Application {
 	}
 
 	stateEvents := make(chan StateEvent)
+	events := NewEventBroker()
+	tasks := newTaskSource(database, cfg.Tasks)
+	pomodoroManager := NewPomodoroManager(logger, database, stateEvents)
+	pomodoroManager.SetTaskSource(tasks)
+	if workDir, err := os.Getwd(); err == nil {
+		pomodoroManager.SetWorkDir(workDir)
+	}
+
+	if cfg.Sync.Service != "" && cfg.Sync.AfterPomodoro {
+		timeSync, err := NewTimeSync(logger, database, cfg.Sync)
+		if err != nil {
+			logger.Error("can't start sync after pomodoro", slog.Any("error", err))
+		} else {
+			pomodoroManager.OnFinish(func(*Pomodoro) { go timeSync.SyncInBackground() })
+		}
+	}
 
 	app := &Application{
 		logger:    logger,
-		uiManager: NewUIManager(logger, cfg, stateEvents, NewPomodoroManager(logger, database, stateEvents), database),
+		uiManager: NewUIManager(logger, cfg, stateEvents, pomodoroManager, tasks, events, keys, theme),
+		apiServer: nil,
+		metrics:   nil,
+	}
+
+	if cfg.API.Enabled {
+		app.apiServer = NewAPIServer(logger, cfg.API.Address, app.uiManager.stateManager, tasks,
+			pomodoroManager, events)
+	}
+	if cfg.Metrics.Enabled {
+		app.metrics = NewMetrics(logger, cfg.Metrics.Address, app.uiManager.stateManager, tasks,
+			pomodoroManager)
+		app.uiManager.AddStateListener(app.metrics.Events())
 	}
 
 	return app
@@ -42,4 +75,23 @@ func (app *Application) Run() {
 
 	go app.uiManager.InitStateAndKeyboardHandling()
 	go app.uiManager.pomodoroTracker.HandlePomodoroStateChanges()
+	go app.uiManager.WatchConfig()
+	go app.uiManager.WatchTasks()
+
+	if app.apiServer != nil {
+		go func() {
+			if err := app.apiServer.Run(); err != nil {
+				app.logger.Error("api server", slog.Any("error", err))
+			}
+		}()
+	}
+
+	if app.metrics != nil {
+		go app.metrics.HandleStateChanges()
+		go func() {
+			if err := app.metrics.Run(); err != nil {
+				app.logger.Error("metrics server", slog.Any("error", err))
+			}
+		}()
+	}
 }
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	glyphHeight = 5
+	glyphGap    = 1
+	// terminal cells are about twice as tall as wide
+	cellAspect = 2
+)
+
+// bigGlyphs is a block font for the clock, '#' is filled cell.
+//
+//nolint:gochecknoglobals // read only font table
+var bigGlyphs = map[rune][glyphHeight]string{
+	'0': {"###", "# #", "# #", "# #", "###"},
+	'1': {"## ", " # ", " # ", " # ", "###"},
+	'2': {"###", "  #", "###", "#  ", "###"},
+	'3': {"###", "  #", "###", "  #", "###"},
+	'4': {"# #", "# #", "###", "  #", "  #"},
+	'5': {"###", "#  ", "###", "  #", "###"},
+	'6': {"###", "#  ", "###", "# #", "###"},
+	'7': {"###", "  #", "  #", "  #", "  #"},
+	'8': {"###", "# #", "###", "# #", "###"},
+	'9': {"###", "# #", "###", "  #", "###"},
+	':': {" ", "#", " ", "#", " "},
+	'+': {"   ", " # ", "###", " # ", "   "},
+}
+
+// bigTextWidth returns width of text in font cells.
+func bigTextWidth(text string) int {
+	width := 0
+	for i, ch := range []rune(text) {
+		if i > 0 {
+			width += glyphGap
+		}
+		width += len(bigGlyphs[ch][0])
+	}
+	return width
+}
+
+// bigClockScale returns horizontal and vertical scale to fit text into area, zeros if it doesn't fit.
+func bigClockScale(text string, width, height int) (int, int) {
+	textWidth := bigTextWidth(text)
+	if textWidth == 0 {
+		return 0, 0
+	}
+
+	scale := min(width/(textWidth*cellAspect), height/glyphHeight)
+	if scale >= 1 {
+		return scale * cellAspect, scale
+	}
+	if width >= textWidth && height >= glyphHeight {
+		return 1, 1
+	}
+	return 0, 0
+}
+
+// drawBigClock draws text centered in area with block font, returns false if area is too small.
+func drawBigClock(screen tcell.Screen, x, y, width, height int, text string, style tcell.Style) bool {
+	sx, sy := bigClockScale(text, width, height)
+	if sx == 0 {
+		return false
+	}
+
+	left := x + (width-bigTextWidth(text)*sx)/2
+	top := y + (height-glyphHeight*sy)/2
+
+	for _, ch := range text {
+		glyph := bigGlyphs[ch]
+		for row, line := range glyph {
+			for col, cell := range line {
+				if cell != '#' {
+					continue
+				}
+				for dy := range sy {
+					for dx := range sx {
+						screen.SetContent(left+col*sx+dx, top+row*sy+dy, '█', nil, style)
+					}
+				}
+			}
+		}
+		left += (len(glyph[0]) + glyphGap) * sx
+	}
+	return true
+}
+
+// progressBar returns markup of bar with elapsed part of total, e.g. "█████░░░░░  50%".
+func progressBar(elapsed, total time.Duration, width int, filled, empty themeStyle) string {
+	const (
+		percentWidth = 5
+		percents     = 100
+	)
+
+	barWidth := width - percentWidth
+	if barWidth <= 0 || total <= 0 {
+		return ""
+	}
+
+	ratio := min(max(float64(elapsed)/float64(total), 0), 1)
+	done := int(ratio * float64(barWidth))
+
+	return fmt.Sprintf("%s%s %3d%%",
+		filled.Wrap(strings.Repeat("█", done)),
+		empty.Wrap(strings.Repeat("░", barWidth-done)),
+		int(ratio*percents))
+}
+
+// drawCountdown draws title, remaining time and progress bar.
+func (m *UIManager) drawCountdown(screen tcell.Screen, x, y, width, height int, style themeStyle,
+	title string, timerType TimerType) {
+	const maxProgressWidth = 60
+
+	remaining := m.stateManager.timeToFinish(timerType)
+	total := m.stateManager.length(timerType)
+	text := formatDuration(remaining)
+	bar := progressBar(total-remaining, total, min(width-2, maxProgressWidth), style, m.theme.Muted)
+
+	// flow mode counts up past zero in its own color
+	if overtime := m.stateManager.overtime(timerType); overtime > 0 {
+		style = m.theme.Overtime
+		text = "+" + formatDuration(overtime)
+		bar = progressBar(total, total, min(width-2, maxProgressWidth), style, m.theme.Muted)
+	}
+
+	if m.stateManager.IsFocusTimeHidden() && timerType == FocusTimer {
+		top := y + (height-2)/2
+		printClockLine(screen, style.Wrap(title), x, y, width, height, top)
+		printClockLine(screen, style.Wrap("focusing"), x, y, width, height, top+1)
+		return
+	}
+
+	drawClock(screen, x, y, width, height, style, title, text, bar)
+}
+
+// drawClock draws title, time and footer line, e.g. progress bar. Big digits are used when
+// area is large enough, otherwise everything fits in three lines.
+func drawClock(screen tcell.Screen, x, y, width, height int, style themeStyle, title, text, footer string) {
+	// title and footer, each separated from the clock by empty line
+	const reservedLines = 4
+
+	printLine := func(line string, row int) {
+		printClockLine(screen, line, x, y, width, height, row)
+	}
+
+	if _, sy := bigClockScale(text, width, height-reservedLines); sy > 0 {
+		blockHeight := glyphHeight*sy + reservedLines
+		top := y + (height-blockHeight)/2
+
+		fg := style.fg
+		if fg == tcell.ColorDefault {
+			fg = tview.Styles.PrimaryTextColor
+		}
+		cellStyle := tcell.StyleDefault.Foreground(fg).Background(tview.Styles.PrimitiveBackgroundColor)
+
+		printLine(style.Wrap(title), top)
+		drawBigClock(screen, x, top+2, width, glyphHeight*sy, text, cellStyle)
+		printLine(footer, top+blockHeight-1)
+		return
+	}
+
+	top := y + (height-3)/2
+	printLine(style.Wrap(title), top)
+	printLine(style.Wrap(text), top+1)
+	printLine(footer, top+2)
+}
+
+// printClockLine prints centered line if row is inside the area.
+func printClockLine(screen tcell.Screen, line string, x, y, width, height, row int) {
+	if row >= y && row < y+height {
+		tview.Print(screen, line, x, row, width, tview.AlignCenter, tview.Styles.PrimaryTextColor)
+	}
+}
@@ -0,0 +1,65 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigClockScale(t *testing.T) {
+	const text = "25:00"
+	require.Equal(t, 17, bigTextWidth(text))
+
+	tests := []struct {
+		name           string
+		width, height  int
+		sx, sy         int
+		expectedToDraw bool
+	}{
+		{"too small", 16, 10, 0, 0, false},
+		{"squeezed", 20, 5, 1, 1, true},
+		{"normal", 40, 6, 2, 1, true},
+		{"big", 120, 30, 6, 3, true},
+		{"limited by height", 200, 11, 4, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sx, sy := bigClockScale(text, tt.width, tt.height)
+			assert.Equal(t, tt.sx, sx)
+			assert.Equal(t, tt.sy, sy)
+		})
+	}
+}
+
+func TestDrawBigClock(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	require.NoError(t, screen.Init())
+	screen.SetSize(40, 5)
+
+	assert.False(t, drawBigClock(screen, 0, 0, 10, 5, "10:00", tcell.StyleDefault))
+	assert.True(t, drawBigClock(screen, 0, 0, 40, 5, "10:00", tcell.StyleDefault))
+
+	// "1" glyph starts at column 3 and it's twice wider: "## " then " # "
+	for _, cell := range []struct {
+		x, y   int
+		filled bool
+	}{{3, 0, true}, {6, 0, true}, {7, 0, false}, {3, 1, false}, {5, 1, true}} {
+		ch, _, _, _ := screen.GetContent(cell.x, cell.y)
+		assert.Equal(t, cell.filled, ch == '█', "cell %d:%d", cell.x, cell.y)
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	bar := progressBar(5*time.Minute, 20*time.Minute, 13, themeStyle{}, themeStyle{})
+	assert.Equal(t, "[::]██[-:-:-][::]░░░░░░[-:-:-]  25%", bar)
+
+	bar = progressBar(30*time.Minute, 20*time.Minute, 7, themeStyle{}, themeStyle{})
+	assert.Equal(t, "[::]██[-:-:-][::][-:-:-] 100%", bar)
+
+	assert.Empty(t, progressBar(time.Minute, time.Minute, 3, themeStyle{}, themeStyle{}))
+}
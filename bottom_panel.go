@@ -1,41 +1,43 @@
 package main
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/rivo/tview"
 )
 
-func constructBottomPanel(curPage PageName) *tview.TextView {
-	textPanel := hotKeysForPanel(curPage)
+func (m *UIManager) constructBottomPanel(curPage PageName) *tview.TextView {
+	textPanel := hotKeysForPanel(curPage, m.keys, m.theme)
 	panel := tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignCenter).
 		SetText(textPanel)
 
 	return panel
 }
 
-func hotKeysForPanel(pageName PageName) string {
+func hotKeysForPanel(pageName PageName, keys *KeyBindings, theme *Theme) string {
 	type keyWithPage struct {
-		key            string
+		action         Action
 		prettyPageName string
 
 		insidePages []PageName
 	}
 
-	keys := []keyWithPage{
-		{"1", "Focus", []PageName{pauseFocusPage, activeFocusPage}},
-		{"2", "Break", []PageName{pauseBreakPage, activeBreakPage}},
-		{"3", "Tasks", []PageName{allTasksPage, addNewTaskPage, deleteTaskPage}},
-		{"4", "Summary", []PageName{summaryStatsPage}},
-		{"5", "Detail", []PageName{detailStatsPage, insertStatsPage}},
+	pages := []keyWithPage{
+		{actionFocusPage, "Focus", []PageName{pauseFocusPage, activeFocusPage}},
+		{actionBreakPage, "Break", []PageName{pauseBreakPage, activeBreakPage}},
+		{actionTasksPage, "Tasks", []PageName{allTasksPage, addNewTaskPage, deleteTaskPage}},
+		{actionSummaryPage, "Summary", []PageName{summaryStatsPage}},
+		{actionDetailPage, "Detail", []PageName{detailStatsPage, insertStatsPage}},
+		{actionSettingsPage, "Settings", []PageName{settingsPage}},
+		{actionFlowtimePage, "Flowtime", []PageName{pauseStopwatchPage, activeStopwatchPage}},
 	}
 
-	strs := make([]string, 0, len(keys))
-	for _, k := range keys {
-		str := fmt.Sprintf("%s[:gray]%s[:-]", k.key, k.prettyPageName)
+	strs := make([]string, 0, len(pages))
+	for _, k := range pages {
+		key := tview.Escape(keys.Name(k.action))
+		str := key + theme.PageOther.Wrap(k.prettyPageName)
 		if containPage(pageName, k.insidePages) {
-			str = fmt.Sprintf("%s[:brown]%s[:-]", k.key, k.prettyPageName)
+			str = key + theme.PageActive.Wrap(k.prettyPageName)
 		}
 		strs = append(strs, str)
 	}
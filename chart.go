@@ -6,7 +6,7 @@ import (
 	"time"
 )
 
-func CreateBarGraph(data [7]int) string {
+func CreateBarGraph(data [7]int, today themeStyle) string {
 	var graph strings.Builder
 	maxValue := 6
 	for _, value := range data {
@@ -29,7 +29,7 @@ func CreateBarGraph(data [7]int) string {
 	graph.WriteString("    ")
 	for i, day := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
 		if int((time.Now().Weekday()+6)%7) == i {
-			day = fmt.Sprintf("[green]%s[-]", day)
+			day = today.Wrap(day)
 		}
 		graph.WriteString(day + "  ")
 	}
@@ -6,8 +6,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -15,24 +18,242 @@ import (
 
 type Config struct {
 	Timer TimerConfig `yaml:"timer"`
+	// Profiles are named timer settings, Timer is used as "default" profile.
+	Profiles []Profile `yaml:"profiles,omitempty"`
+	// Profile is the name of active profile, empty means default.
+	Profile string `yaml:"profile,omitempty"`
+	// Plans are scripted sequences of intervals started from pause pages.
+	Plans []Plan `yaml:"plans,omitempty"`
+	// Flowtime sets break length after stopwatch session.
+	Flowtime FlowtimeConfig `yaml:"flowtime,omitempty"`
+	// Pauses limits how much focus session may be interrupted.
+	Pauses PausesConfig `yaml:"pauses,omitempty"`
+	// Minimum is the shortest focus session counted as pomodoro.
+	Minimum MinimumConfig `yaml:"minimum,omitempty"`
+	// Sync pushes finished pomodoros to time tracking service.
+	Sync SyncConfig `yaml:"sync,omitempty"`
+	// Tasks keeps tasks in a file instead of database.
+	Tasks   TasksConfig   `yaml:"tasks,omitempty"`
+	API     APIConfig     `yaml:"api"`
+	Metrics MetricsConfig `yaml:"metrics"`
+	Theme   ThemeConfig   `yaml:"theme"`
+	UI      UIConfig      `yaml:"ui"`
+	// Keys maps action name to key, e.g. "start_pause: Space" or "tasks_page: Ctrl+T".
+	Keys map[string]string `yaml:"keys,omitempty"`
 }
 
 type TimerConfig struct {
 	FocusDuration   time.Duration `yaml:"focus_duration"`
 	BreakDuration   time.Duration `yaml:"break_duration"`
 	HiddenFocusTime bool          `yaml:"hidden_focus_time"`
+	// FlowMode keeps focus timer counting past zero until it's stopped by hand.
+	FlowMode bool `yaml:"flow_mode"`
+	// Strict forbids pauses and skipped breaks, focus finished early is abandoned.
+	Strict bool `yaml:"strict"`
+}
+
+// Profile is named timer settings, e.g. "deep work" with 50m focus and 10m break.
+type Profile struct {
+	Name  string      `yaml:"name"`
+	Timer TimerConfig `yaml:",inline"`
+}
+
+// Plan is a sequence of steps run one by one, e.g. 3×(50m focus, 10m break), then 30m lunch.
+type Plan struct {
+	Name  string     `yaml:"name"`
+	Steps []PlanStep `yaml:"steps"`
+}
+
+// PlanStep is focus interval followed by break, repeated Repeat times.
+// One of durations can be omitted, e.g. step with break only is a lunch.
+type PlanStep struct {
+	Name   string        `yaml:"name,omitempty"`
+	Focus  time.Duration `yaml:"focus,omitempty"`
+	Break  time.Duration `yaml:"break,omitempty"`
+	Repeat int           `yaml:"repeat,omitempty"`
+}
+
+// Times returns how many times step is repeated.
+func (s PlanStep) Times() int {
+	return max(s.Repeat, 1)
+}
+
+// FlowtimeConfig sizes break after open-ended stopwatch session: focus time divided by Divisor,
+// or fixed break of the first rule the session fits in. Result is kept between MinBreak (1m by default)
+// and MaxBreak.
+type FlowtimeConfig struct {
+	Divisor  int            `yaml:"divisor,omitempty"`
+	MinBreak time.Duration  `yaml:"min_break,omitempty"`
+	MaxBreak time.Duration  `yaml:"max_break,omitempty"`
+	Rules    []FlowtimeRule `yaml:"rules,omitempty"`
+}
+
+// FlowtimeRule gives Break to sessions not longer than UpTo, e.g. 5m break up to 25m focus.
+type FlowtimeRule struct {
+	UpTo  time.Duration `yaml:"up_to"`
+	Break time.Duration `yaml:"break"`
+}
+
+// BreakFor returns break length for focus session of given length.
+func (f FlowtimeConfig) BreakFor(focus time.Duration) time.Duration {
+	divisor := f.Divisor
+	if divisor == 0 {
+		divisor = defaultFlowtimeDivisor
+	}
+	breakDuration := (focus / time.Duration(divisor)).Round(time.Second)
+	for _, rule := range f.Rules {
+		if focus <= rule.UpTo {
+			breakDuration = rule.Break
+			break
+		}
+	}
+
+	minBreak := f.MinBreak
+	if minBreak == 0 {
+		minBreak = defaultFlowtimeMinBreak
+	}
+	breakDuration = max(breakDuration, minBreak)
+	if f.MaxBreak > 0 {
+		breakDuration = min(breakDuration, f.MaxBreak)
+	}
+	return breakDuration
+}
+
+// PausesConfig limits pauses inside one focus session, zero value means no limit.
+// Session over the limit is flagged in statistics, or voided when Void is set.
+type PausesConfig struct {
+	MaxCount int           `yaml:"max_count,omitempty"`
+	MaxTime  time.Duration `yaml:"max_time,omitempty"`
+	Void     bool          `yaml:"void,omitempty"`
+}
+
+// Exceeded tells if session paused count times for paused in total breaks the limit.
+func (p PausesConfig) Exceeded(count int, paused time.Duration) bool {
+	return (p.MaxCount > 0 && count > p.MaxCount) || (p.MaxTime > 0 && paused > p.MaxTime)
+}
+
+// MinimumConfig sets shortest focus session that counts: Duration or Percent of focus duration,
+// the longer of them. Shorter session is stored as incomplete, or deleted when Discard is set.
+type MinimumConfig struct {
+	Duration time.Duration `yaml:"duration,omitempty"`
+	Percent  int           `yaml:"percent,omitempty"`
+	Discard  bool          `yaml:"discard,omitempty"`
+}
+
+// For returns minimum for focus interval of given length, zero means any session counts.
+func (m MinimumConfig) For(focus time.Duration) time.Duration {
+	return max(m.Duration, focus*time.Duration(m.Percent)/100)
+}
+
+// Time tracking services supported by sync.
+const (
+	SyncToggl    = "toggl"
+	SyncClockify = "clockify"
+)
+
+// SyncConfig pushes finished pomodoros as time entries to Toggl or Clockify compatible REST API,
+// empty Service turns sync off. Projects maps task name to project ID, Project is used for other tasks.
+type SyncConfig struct {
+	Service   string            `yaml:"service"`
+	BaseURL   string            `yaml:"base_url,omitempty"`
+	Token     string            `yaml:"token"`
+	Workspace string            `yaml:"workspace"`
+	Project   string            `yaml:"project,omitempty"`
+	Projects  map[string]string `yaml:"projects,omitempty"`
+	// AfterPomodoro syncs right after each pomodoro, otherwise only "pomotrack sync" does it.
+	AfterPomodoro bool `yaml:"after_pomodoro,omitempty"`
+}
+
+// ProjectFor returns project ID for task, empty if task has no project.
+func (s SyncConfig) ProjectFor(task string) string {
+	if project, ok := s.Projects[task]; ok {
+		return project
+	}
+	return s.Project
+}
+
+// Task sources besides database.
+const (
+	TaskSourceTodoTxt  = "todotxt"
+	TaskSourceMarkdown = "markdown"
+)
+
+// TasksConfig selects where tasks are kept, empty Source means database.
+type TasksConfig struct {
+	Source string `yaml:"source,omitempty"`
+	// File is path to tasks file, "~/" is replaced with home directory.
+	File string `yaml:"file,omitempty"`
+}
+
+// Path returns tasks file path with home directory expanded.
+func (t TasksConfig) Path() string {
+	if rest, ok := strings.CutPrefix(t.File, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return t.File
+}
+
+// APIConfig describes local HTTP server, it's disabled by default.
+type APIConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+// MetricsConfig describes prometheus metrics server, it's disabled by default.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+}
+
+// ThemeConfig selects built-in theme by name, non empty colors override it.
+// Colors are names ("red", "darkgreen") or hex values ("#ff8800").
+type ThemeConfig struct {
+	Name       string `yaml:"name"`
+	Background string `yaml:"background,omitempty"`
+	Text       string `yaml:"text,omitempty"`
+	Border     string `yaml:"border,omitempty"`
+	Contrast   string `yaml:"contrast,omitempty"`
+	Focus      string `yaml:"focus,omitempty"`
+	Break      string `yaml:"break,omitempty"`
+	PageActive string `yaml:"page_active,omitempty"`
+	PageOther  string `yaml:"page_other,omitempty"`
+	Muted      string `yaml:"muted,omitempty"`
+	Danger     string `yaml:"danger,omitempty"`
+	Highlight  string `yaml:"highlight,omitempty"`
+	Overtime   string `yaml:"overtime,omitempty"`
+}
+
+// UIConfig holds interface options.
+type UIConfig struct {
+	// ShowKeyTips opens sidebar with keys of current page on startup.
+	ShowKeyTips bool `yaml:"show_key_tips"`
 }
 
 type flags struct {
 	FocusDuration   time.Duration `yaml:"focus_duration"`
 	BreakDuration   time.Duration `yaml:"break_duration"`
 	HiddenFocusTime bool          `yaml:"hidden_focus_time"`
+	FlowMode        bool          `yaml:"flow_mode"`
+	Strict          bool          `yaml:"strict"`
+	Profile         string        `yaml:"profile"`
 }
 
+// DefaultProfile is the name of timer settings from "timer" section.
+const DefaultProfile = "default"
+
 const (
 	configName           = ".pomotrack-config.yaml"
 	defaultFocusDuration = 25 * time.Minute
 	defaultBreakDuration = 5 * time.Minute
+	defaultAPIAddress    = "127.0.0.1:8765"
+	defaultMetricsAddr   = "127.0.0.1:9765"
+	// defaultFlowtimeDivisor gives 10m break after 50m of flow.
+	defaultFlowtimeDivisor  = 5
+	defaultFlowtimeMinBreak = 1 * time.Minute
+	defaultTogglURL         = "https://api.track.toggl.com/api/v9"
+	defaultClockifyURL      = "https://api.clockify.me/api/v1"
 )
 
 func parseFlags() flags {
@@ -41,6 +262,9 @@ func parseFlags() flags {
 	flag.DurationVar(&f.FocusDuration, "focus-duration", 0, "edit focus timer duration")
 	flag.DurationVar(&f.BreakDuration, "break-duration", 0, "edit break timer duration")
 	flag.BoolVar(&f.HiddenFocusTime, "hidden-focus-time", false, "show or hide clock on focus page")
+	flag.BoolVar(&f.FlowMode, "flow-mode", false, "keep counting focus time past zero")
+	flag.BoolVar(&f.Strict, "strict", false, "forbid pauses and skipped breaks")
+	flag.StringVar(&f.Profile, "profile", "", "select timer profile by name")
 	flag.Parse()
 
 	return f
@@ -57,6 +281,14 @@ func Init() (*Config, error) {
 	}
 
 	changed := applyFlagsToConfig(f, config)
+
+	// don't save flags that make config invalid
+	checked := *config
+	setDefaults(&checked)
+	if err = checked.Validate(); err != nil {
+		return nil, err
+	}
+
 	if changed {
 		err = writeConfig(config, configPath)
 		if err != nil {
@@ -64,14 +296,264 @@ func Init() (*Config, error) {
 		}
 	}
 
+	setDefaults(config)
+
+	return config, nil
+}
+
+// Load reads config without command line flags, for commands run instead of the app.
+func Load() (*Config, error) {
+	config, err := readConfig(getConfigPath())
+	if err != nil {
+		return nil, fmt.Errorf("can't read config: %w", err)
+	}
+
+	setDefaults(config)
+	if err = config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ActiveProfile returns name of the active timer profile.
+func (c *Config) ActiveProfile() string {
+	if c.Profile == "" {
+		return DefaultProfile
+	}
+	return c.Profile
+}
+
+// ActiveTimer returns timer settings of the active profile.
+func (c *Config) ActiveTimer() TimerConfig {
+	return *c.activeTimerRef()
+}
+
+// activeTimerRef returns timer settings of the active profile to change them in place.
+func (c *Config) activeTimerRef() *TimerConfig {
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == c.Profile {
+			return &c.Profiles[i].Timer
+		}
+	}
+	return &c.Timer
+}
+
+// ProfileNames returns default profile and named profiles in config order.
+func (c *Config) ProfileNames() []string {
+	names := []string{DefaultProfile}
+	for _, profile := range c.Profiles {
+		names = append(names, profile.Name)
+	}
+	return names
+}
+
+func setDefaults(config *Config) {
 	if config.Timer.FocusDuration == 0 {
 		config.Timer.FocusDuration = defaultFocusDuration
 	}
 	if config.Timer.BreakDuration == 0 {
 		config.Timer.BreakDuration = defaultBreakDuration
 	}
+	if config.API.Address == "" {
+		config.API.Address = defaultAPIAddress
+	}
+	if config.Metrics.Address == "" {
+		config.Metrics.Address = defaultMetricsAddr
+	}
+	if config.Sync.BaseURL == "" {
+		switch config.Sync.Service {
+		case SyncToggl:
+			config.Sync.BaseURL = defaultTogglURL
+		case SyncClockify:
+			config.Sync.BaseURL = defaultClockifyURL
+		}
+	}
+}
 
-	return config, nil
+// Watch polls config file and calls onChange with new config every time the file is modified.
+// Broken files are reported to onError and skipped, so caller keeps the last good config.
+func Watch(interval time.Duration, onChange func(*Config), onError func(error)) {
+	watchFile(getConfigPath(), interval, nil, onChange, onError)
+}
+
+func watchFile(configPath string, interval time.Duration, stop <-chan struct{},
+	onChange func(*Config), onError func(error)) {
+	lastMod, lastSize := fileVersion(configPath)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+
+		modTime, size := fileVersion(configPath)
+		// editors may remove file for a moment while saving it
+		if modTime.IsZero() || (modTime.Equal(lastMod) && size == lastSize) {
+			continue
+		}
+		lastMod, lastSize = modTime, size
+
+		config, err := readConfig(configPath)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		setDefaults(config)
+		if err = config.Validate(); err != nil {
+			onError(err)
+			continue
+		}
+		onChange(config)
+	}
+}
+
+func fileVersion(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
+
+// Save writes config to the file it was read from.
+func Save(config *Config) error {
+	return writeConfig(config, getConfigPath())
+}
+
+var errInvalidConfig = errors.New("invalid config")
+
+// Validate checks durations, profiles and server addresses.
+func (c *Config) Validate() error {
+	if err := validateTimer(c.Timer); err != nil {
+		return err
+	}
+
+	names := map[string]bool{DefaultProfile: true}
+	for _, profile := range c.Profiles {
+		if profile.Name == "" || names[profile.Name] {
+			return fmt.Errorf("%w: profile name %q is empty or used twice", errInvalidConfig, profile.Name)
+		}
+		names[profile.Name] = true
+
+		if err := validateTimer(profile.Timer); err != nil {
+			return fmt.Errorf("profile %s: %w", profile.Name, err)
+		}
+	}
+	if c.Profile != "" && !names[c.Profile] {
+		return fmt.Errorf("%w: unknown profile %q", errInvalidConfig, c.Profile)
+	}
+	if err := validatePlans(c.Plans); err != nil {
+		return err
+	}
+	if err := validateFlowtime(c.Flowtime); err != nil {
+		return err
+	}
+	if c.Pauses.MaxCount < 0 || c.Pauses.MaxTime < 0 {
+		return fmt.Errorf("%w: pause limits can't be negative", errInvalidConfig)
+	}
+	if c.Minimum.Duration < 0 || c.Minimum.Percent < 0 || c.Minimum.Percent > 100 {
+		return fmt.Errorf("%w: minimum session needs positive duration and percent up to 100", errInvalidConfig)
+	}
+	if err := validateSync(c.Sync); err != nil {
+		return err
+	}
+	if err := validateTasks(c.Tasks); err != nil {
+		return err
+	}
+
+	if _, _, err := net.SplitHostPort(c.API.Address); err != nil {
+		return fmt.Errorf("%w: api address: %w", errInvalidConfig, err)
+	}
+	if _, _, err := net.SplitHostPort(c.Metrics.Address); err != nil {
+		return fmt.Errorf("%w: metrics address: %w", errInvalidConfig, err)
+	}
+	return nil
+}
+
+func validatePlans(plans []Plan) error {
+	names := make(map[string]bool, len(plans))
+	for _, plan := range plans {
+		if plan.Name == "" || names[plan.Name] {
+			return fmt.Errorf("%w: plan name %q is empty or used twice", errInvalidConfig, plan.Name)
+		}
+		names[plan.Name] = true
+
+		if len(plan.Steps) == 0 {
+			return fmt.Errorf("%w: plan %s has no steps", errInvalidConfig, plan.Name)
+		}
+		for i, step := range plan.Steps {
+			if step.Focus < 0 || step.Break < 0 || step.Focus+step.Break == 0 || step.Repeat < 0 {
+				return fmt.Errorf("%w: plan %s: step %d needs positive focus or break", errInvalidConfig,
+					plan.Name, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+func validateFlowtime(f FlowtimeConfig) error {
+	if f.Divisor < 0 || f.MinBreak < 0 || f.MaxBreak < 0 {
+		return fmt.Errorf("%w: flowtime values can't be negative", errInvalidConfig)
+	}
+	if f.MaxBreak > 0 && f.MaxBreak < f.MinBreak {
+		return fmt.Errorf("%w: flowtime max break is less than min break", errInvalidConfig)
+	}
+	var prev time.Duration
+	for i, rule := range f.Rules {
+		if rule.UpTo <= prev || rule.Break <= 0 {
+			return fmt.Errorf("%w: flowtime rule %d needs positive break and up_to greater than previous",
+				errInvalidConfig, i+1)
+		}
+		prev = rule.UpTo
+	}
+	return nil
+}
+
+func validateSync(s SyncConfig) error {
+	switch s.Service {
+	case "":
+		return nil
+	case SyncToggl, SyncClockify:
+	default:
+		return fmt.Errorf("%w: sync service must be %s or %s", errInvalidConfig, SyncToggl, SyncClockify)
+	}
+
+	if s.Token == "" || s.Workspace == "" {
+		return fmt.Errorf("%w: sync needs token and workspace", errInvalidConfig)
+	}
+	if _, err := url.ParseRequestURI(s.BaseURL); err != nil {
+		return fmt.Errorf("%w: sync base url: %w", errInvalidConfig, err)
+	}
+	return nil
+}
+
+func validateTasks(t TasksConfig) error {
+	switch t.Source {
+	case "":
+		return nil
+	case TaskSourceTodoTxt, TaskSourceMarkdown:
+	default:
+		return fmt.Errorf("%w: unknown tasks source %q", errInvalidConfig, t.Source)
+	}
+
+	if t.File == "" {
+		return fmt.Errorf("%w: tasks source %s needs file", errInvalidConfig, t.Source)
+	}
+	return nil
+}
+
+func validateTimer(timer TimerConfig) error {
+	if timer.FocusDuration <= 0 {
+		return fmt.Errorf("%w: focus duration must be positive", errInvalidConfig)
+	}
+	if timer.BreakDuration <= 0 {
+		return fmt.Errorf("%w: break duration must be positive", errInvalidConfig)
+	}
+	return nil
 }
 
 func readConfig(configPath string) (*Config, error) {
@@ -134,18 +616,38 @@ func writeConfig(config *Config, configPath string) error {
 func applyFlagsToConfig(f flags, c *Config) bool {
 	var changed bool
 
-	if f.FocusDuration > 0 && f.FocusDuration != c.Timer.FocusDuration {
-		c.Timer.FocusDuration = f.FocusDuration
+	// profile is selected first, timer flags change settings of the selected one
+	if f.Profile != "" && f.Profile != c.ActiveProfile() {
+		c.Profile = f.Profile
+		if f.Profile == DefaultProfile {
+			c.Profile = ""
+		}
+		changed = true
+	}
+	timer := c.activeTimerRef()
+
+	if f.FocusDuration > 0 && f.FocusDuration != timer.FocusDuration {
+		timer.FocusDuration = f.FocusDuration
+		changed = true
+	}
+
+	if f.BreakDuration > 0 && f.BreakDuration != timer.BreakDuration {
+		timer.BreakDuration = f.BreakDuration
+		changed = true
+	}
+
+	if isFlagPassed("hidden-focus-time") && f.HiddenFocusTime != timer.HiddenFocusTime {
+		timer.HiddenFocusTime = f.HiddenFocusTime
 		changed = true
 	}
 
-	if f.BreakDuration > 0 && f.BreakDuration != c.Timer.BreakDuration {
-		c.Timer.BreakDuration = f.BreakDuration
+	if isFlagPassed("flow-mode") && f.FlowMode != timer.FlowMode {
+		timer.FlowMode = f.FlowMode
 		changed = true
 	}
 
-	if isFlagPassed("hidden-focus-time") && f.HiddenFocusTime != c.Timer.HiddenFocusTime {
-		c.Timer.HiddenFocusTime = f.HiddenFocusTime
+	if isFlagPassed("strict") && f.Strict != timer.Strict {
+		timer.Strict = f.Strict
 		changed = true
 	}
 	return changed
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), configName)
+	require.NoError(t, os.WriteFile(path, []byte("timer:\n  focus_duration: 25m\n"), 0o600))
+
+	configs := make(chan *Config, 1)
+	errs := make(chan error, 1)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go watchFile(path, 10*time.Millisecond, stop,
+		func(c *Config) { configs <- c },
+		func(err error) { errs <- err })
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, os.WriteFile(path, []byte("timer: [broken"), 0o600))
+	select {
+	case err := <-errs:
+		require.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("broken config wasn't reported")
+	}
+
+	require.NoError(t, os.WriteFile(path, []byte("timer:\n  focus_duration: 50m\n"), 0o600))
+	select {
+	case c := <-configs:
+		assert.Equal(t, 50*time.Minute, c.Timer.FocusDuration)
+		assert.Equal(t, defaultBreakDuration, c.Timer.BreakDuration)
+		assert.Equal(t, defaultAPIAddress, c.API.Address)
+	case <-time.After(time.Second):
+		t.Fatal("changed config wasn't reloaded")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	config := &Config{}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+
+	config.Timer.BreakDuration = -time.Minute
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Timer.BreakDuration = time.Minute
+	config.API.Address = "localhost"
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
+
+func TestConfig_Profiles(t *testing.T) {
+	config := &Config{
+		Timer: TimerConfig{FocusDuration: 25 * time.Minute, BreakDuration: 5 * time.Minute},
+		Profiles: []Profile{
+			{Name: "deep work", Timer: TimerConfig{FocusDuration: 50 * time.Minute, BreakDuration: 10 * time.Minute}},
+			{Name: "meetings", Timer: TimerConfig{FocusDuration: 15 * time.Minute, BreakDuration: 3 * time.Minute}},
+		},
+	}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+
+	assert.Equal(t, DefaultProfile, config.ActiveProfile())
+	assert.Equal(t, 25*time.Minute, config.ActiveTimer().FocusDuration)
+	assert.Equal(t, []string{DefaultProfile, "deep work", "meetings"}, config.ProfileNames())
+
+	config.Profile = "deep work"
+	assert.Equal(t, 50*time.Minute, config.ActiveTimer().FocusDuration)
+
+	config.Profile = "unknown"
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Profile = ""
+	config.Profiles = append(config.Profiles, Profile{Name: "meetings", Timer: config.Timer})
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
+
+func TestConfig_ValidatePlans(t *testing.T) {
+	config := &Config{
+		Plans: []Plan{{Name: "exam prep", Steps: []PlanStep{
+			{Focus: 50 * time.Minute, Break: 10 * time.Minute, Repeat: 3},
+			{Name: "lunch", Break: 30 * time.Minute},
+		}}},
+	}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, 3, config.Plans[0].Steps[0].Times())
+	assert.Equal(t, 1, config.Plans[0].Steps[1].Times())
+
+	config.Plans[0].Steps = append(config.Plans[0].Steps, PlanStep{Name: "empty"})
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Plans[0].Steps = nil
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
+
+func TestFlowtimeConfig_BreakFor(t *testing.T) {
+	assert.Equal(t, 10*time.Minute, FlowtimeConfig{}.BreakFor(50*time.Minute))
+	assert.Equal(t, 3*time.Minute, FlowtimeConfig{MinBreak: 3 * time.Minute}.BreakFor(5*time.Minute))
+
+	flowtime := FlowtimeConfig{
+		Divisor:  6,
+		MinBreak: 3 * time.Minute,
+		MaxBreak: 15 * time.Minute,
+		Rules:    []FlowtimeRule{{UpTo: 25 * time.Minute, Break: 5 * time.Minute}},
+	}
+	assert.Equal(t, 5*time.Minute, flowtime.BreakFor(25*time.Minute))
+	assert.Equal(t, 4*time.Minute+20*time.Second, flowtime.BreakFor(26*time.Minute))
+	assert.Equal(t, 10*time.Minute, flowtime.BreakFor(time.Hour))
+	assert.Equal(t, 15*time.Minute, flowtime.BreakFor(3*time.Hour))
+}
+
+func TestConfig_ValidateFlowtime(t *testing.T) {
+	config := &Config{Flowtime: FlowtimeConfig{Rules: []FlowtimeRule{
+		{UpTo: 25 * time.Minute, Break: 5 * time.Minute},
+		{UpTo: 50 * time.Minute, Break: 8 * time.Minute},
+	}}}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+
+	config.Flowtime.Rules[1].UpTo = 20 * time.Minute
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Flowtime = FlowtimeConfig{MinBreak: 10 * time.Minute, MaxBreak: 5 * time.Minute}
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
+
+func TestPausesConfig_Exceeded(t *testing.T) {
+	assert.False(t, PausesConfig{}.Exceeded(10, time.Hour))
+
+	limit := PausesConfig{MaxCount: 2, MaxTime: 10 * time.Minute}
+	assert.False(t, limit.Exceeded(2, 10*time.Minute))
+	assert.True(t, limit.Exceeded(3, time.Minute))
+	assert.True(t, limit.Exceeded(1, 11*time.Minute))
+}
+
+func TestMinimumConfig_For(t *testing.T) {
+	assert.Zero(t, MinimumConfig{}.For(25*time.Minute))
+	assert.Equal(t, 10*time.Minute, MinimumConfig{Percent: 40}.For(25*time.Minute))
+	assert.Equal(t, 15*time.Minute, MinimumConfig{Duration: 15 * time.Minute, Percent: 40}.For(25*time.Minute))
+}
+
+func TestConfig_ValidateSync(t *testing.T) {
+	config := &Config{Sync: SyncConfig{Service: SyncToggl, Token: "secret", Workspace: "42"}}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+	assert.Equal(t, defaultTogglURL, config.Sync.BaseURL)
+
+	config.Sync.Service = "harvest"
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Sync = SyncConfig{Service: SyncClockify, BaseURL: "http://127.0.0.1:9000"}
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
+
+func TestConfig_ValidateTasks(t *testing.T) {
+	config := &Config{Tasks: TasksConfig{Source: TaskSourceTodoTxt, File: "~/todo.txt"}}
+	setDefaults(config)
+	require.NoError(t, config.Validate())
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "todo.txt"), config.Tasks.Path())
+
+	config.Tasks.File = ""
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+
+	config.Tasks = TasksConfig{Source: "notion", File: "tasks"}
+	require.ErrorIs(t, config.Validate(), errInvalidConfig)
+}
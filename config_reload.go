@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/rivo/tview"
+)
+
+const (
+	configPollInterval = 1 * time.Second
+	noticeDuration     = 3 * time.Second
+)
+
+// WatchConfig reloads config when its file is changed, blocks forever.
+func (m *UIManager) WatchConfig() {
+	config.Watch(configPollInterval, m.reloadConfig, func(err error) {
+		m.ui.QueueUpdateDraw(func() {
+			m.reportConfigError(err)
+		})
+	})
+}
+
+func (m *UIManager) reloadConfig(cfg *config.Config) {
+	m.ui.QueueUpdateDraw(func() {
+		// file was written by settings page
+		if reflect.DeepEqual(cfg, m.config) {
+			return
+		}
+
+		keys, err := NewKeyBindings(cfg.Keys)
+		if err != nil {
+			m.reportConfigError(fmt.Errorf("invalid keys: %w", err))
+			return
+		}
+		theme, err := NewTheme(cfg.Theme)
+		if err != nil {
+			m.reportConfigError(fmt.Errorf("invalid theme: %w", err))
+			return
+		}
+
+		message := "Config reloaded"
+		restart := m.restartNeeded(cfg, theme)
+		if restart {
+			message += ", some changes apply after restart"
+		}
+
+		m.applyConfig(cfg, keys, theme)
+		if m.currentPage != nil {
+			m.AddPageAndSwitch(m.currentPage)
+		}
+		m.logger.Info("config reloaded", slog.Bool("restart_needed", restart))
+		m.showNotice(m.theme.Highlight.Wrap(message))
+	})
+}
+
+// reportConfigError keeps last good config and tells user why new one is ignored.
+func (m *UIManager) reportConfigError(err error) {
+	m.logger.Error("can't reload config", slog.Any("error", err))
+	m.showNotice(m.theme.Danger.Wrap(tview.Escape("Config not reloaded: " + err.Error())))
+}
+
+// restartNeeded reports whether new config changes what is set up only at start:
+// API and metrics servers, sync, tasks source and monochrome mode.
+func (m *UIManager) restartNeeded(cfg *config.Config, theme *Theme) bool {
+	return cfg.API != m.config.API || cfg.Metrics != m.config.Metrics ||
+		theme.Monochrome != m.theme.Monochrome || cfg.Tasks != m.config.Tasks ||
+		!reflect.DeepEqual(cfg.Sync, m.config.Sync)
+}
+
+// applyConfig switches running app to new config. Running timer isn't touched,
+// API and metrics servers keep addresses they were started with.
+func (m *UIManager) applyConfig(cfg *config.Config, keys *KeyBindings, theme *Theme) {
+	m.config = cfg
+	m.stateManager.SetProfile(cfg.ActiveProfile(), cfg.ActiveTimer())
+	m.stateManager.SetFlowtime(cfg.Flowtime)
+	m.stateManager.SetMinimum(cfg.Minimum)
+	m.pomodoroTracker.SetPauseLimit(cfg.Pauses)
+	m.pomodoroTracker.SetDiscardShort(cfg.Minimum.Discard)
+	m.keys = keys
+	m.theme = theme
+	m.theme.Apply()
+	m.showKeyTips = cfg.UI.ShowKeyTips
+}
+
+// showNotice replaces text of bottom panel for a few seconds.
+func (m *UIManager) showNotice(text string) {
+	panel := m.bottomPanel
+	if panel == nil {
+		return
+	}
+	hotKeys := panel.GetText(false)
+	panel.SetText(text)
+
+	time.AfterFunc(noticeDuration, func() {
+		m.ui.QueueUpdateDraw(func() {
+			// page could be switched and panel rebuilt already
+			if panel.GetText(false) == text {
+				panel.SetText(hotKeys)
+			}
+		})
+	})
+}
@@ -16,13 +16,21 @@ import (
 )
 
 type Task struct {
-	ID                 int       `db:"id"`
-	Name               string    `db:"name"`
-	PomodorosRequired  int       `db:"pomodoros_requires"`
-	PomodorosCompleted int       `db:"pomodoros_completed"`
-	IsComplete         bool      `db:"is_complete"`
-	IsActive           bool      `db:"is_active"`
-	CreateAt           time.Time `db:"created_at"`
+	ID                 int       `db:"id"                  json:"id"`
+	Name               string    `db:"name"                json:"name"`
+	PomodorosRequired  int       `db:"pomodoros_requires"  json:"pomodoros_required"`
+	PomodorosCompleted int       `db:"pomodoros_completed" json:"pomodoros_completed"`
+	IsComplete         bool      `db:"is_complete"         json:"is_complete"`
+	IsActive           bool      `db:"is_active"           json:"is_active"`
+	CreateAt           time.Time `db:"created_at"          json:"created_at"`
+	// UUID is ID of task imported from Taskwarrior or exported to it, empty for other tasks.
+	UUID string `db:"uuid" json:"uuid,omitempty"`
+	// CompleteAt is the moment task became complete, zero for incomplete tasks and tasks
+	// completed before it was kept.
+	CompleteAt time.Time `db:"completed_at" json:"-"`
+
+	// line is text of tasks file task was read from, it's checked before the line is changed
+	line string
 }
 
 //go:embed migrations/*.sql
@@ -55,10 +63,22 @@ func (s *Storage) Migrate() error {
 	return nil
 }
 
+// CreatePomodoro saves new session, session without kind and outcome is a completed focus.
 func (s *Storage) CreatePomodoro(pomodoro *Pomodoro) error {
-	query := `INSERT INTO pomodoros (start_at, finish_at, duration) VALUES (?, ?, ?) RETURNING id;`
+	if pomodoro.Kind == "" {
+		pomodoro.Kind = SessionFocus
+	}
+	if pomodoro.Outcome == "" {
+		pomodoro.Outcome = OutcomeCompleted
+	}
 
-	args := []any{pomodoro.StartAt, pomodoro.FinishAt, pomodoro.SecondsDuration}
+	query := `INSERT INTO pomodoros (start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id;`
+
+	args := []any{pomodoro.StartAt, pomodoro.FinishAt, pomodoro.SecondsDuration, pomodoro.Profile,
+		pomodoro.AdjustedSeconds, pomodoro.OvertimeSeconds, pomodoro.Kind, pomodoro.Outcome, pomodoro.Task,
+		pomodoro.GitRepo, pomodoro.GitBranch}
 
 	err := s.DB.QueryRow(query, args...).Scan(&pomodoro.ID)
 	if err != nil {
@@ -68,9 +88,11 @@ func (s *Storage) CreatePomodoro(pomodoro *Pomodoro) error {
 }
 
 func (s *Storage) UpdatePomodoro(pomodoro *Pomodoro) error {
-	query := `UPDATE pomodoros SET finish_at = ?, duration = ? WHERE id = ?;`
+	query := `UPDATE pomodoros SET finish_at = ?, duration = ?, adjusted = ?, overtime = ?, outcome = ?
+			WHERE id = ?;`
 
-	args := []any{pomodoro.FinishAt, pomodoro.SecondsDuration, pomodoro.ID}
+	args := []any{pomodoro.FinishAt, pomodoro.SecondsDuration, pomodoro.AdjustedSeconds, pomodoro.OvertimeSeconds,
+		pomodoro.Outcome, pomodoro.ID}
 
 	_, err := s.DB.Exec(query, args...)
 	if err != nil {
@@ -79,6 +101,51 @@ func (s *Storage) UpdatePomodoro(pomodoro *Pomodoro) error {
 	return nil
 }
 
+// SubtractFromPomodoro removes seconds from session and from the end of its last segment,
+// session stored before segments has only its duration changed.
+func (s *Storage) SubtractFromPomodoro(id int, seconds int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("can't begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // no-op after commit
+
+	result, err := tx.Exec(`UPDATE pomodoros SET duration = max(duration - ?, 0) WHERE id = ?`, seconds, id)
+	if err != nil {
+		return fmt.Errorf("can't update pomodoro: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil || updated == 0 {
+		return fmt.Errorf("can't update pomodoro %d: %w", id, errNoFinishedPomodoro)
+	}
+
+	var segment Segment
+	err = tx.QueryRow(`SELECT id, finish_at, duration FROM pomodoro_segments
+			WHERE pomodoro_id = ? ORDER BY start_at DESC LIMIT 1`, id).
+		Scan(&segment.ID, &segment.FinishAt, &segment.SecondsDuration)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("can't get last segment: %w", err)
+	default:
+		cut := min(seconds, segment.SecondsDuration)
+		finishAt := segment.FinishAt.Add(-time.Duration(cut) * time.Second)
+		_, err = tx.Exec(`UPDATE pomodoro_segments SET finish_at = ?, duration = ? WHERE id = ?`,
+			finishAt, segment.SecondsDuration-cut, segment.ID)
+		if err != nil {
+			return fmt.Errorf("can't update segment: %w", err)
+		}
+		_, err = tx.Exec(`UPDATE pomodoros SET finish_at = ? WHERE id = ?`, finishAt, id)
+		if err != nil {
+			return fmt.Errorf("can't update pomodoro: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("can't commit pomodoro change: %w", err)
+	}
+	return nil
+}
+
 func (s *Storage) RemovePomodoro(id int) error {
 	query := `DELETE FROM pomodoros WHERE id = ?`
 
@@ -86,25 +153,135 @@ func (s *Storage) RemovePomodoro(id int) error {
 	if err != nil {
 		return fmt.Errorf("can't remove pomodoro: %w", err)
 	}
+
+	// sqlite doesn't enforce foreign keys by default
+	_, err = s.DB.Exec(`DELETE FROM pomodoro_segments WHERE pomodoro_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("can't remove pomodoro segments: %w", err)
+	}
+
+	// id of removed pomodoro can be given to a new one, which must be synced again
+	_, err = s.DB.Exec(`DELETE FROM synced_entries WHERE pomodoro_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("can't remove pomodoro sync records: %w", err)
+	}
 	return nil
 }
 
+// CreateSegment saves time session was running between two pauses.
+func (s *Storage) CreateSegment(segment *Segment) error {
+	query := `INSERT INTO pomodoro_segments (pomodoro_id, start_at, finish_at, duration)
+			VALUES (?, ?, ?, ?) RETURNING id;`
+
+	args := []any{segment.PomodoroID, segment.StartAt, segment.FinishAt, segment.SecondsDuration}
+
+	err := s.DB.QueryRow(query, args...).Scan(&segment.ID)
+	if err != nil {
+		return fmt.Errorf("can't create segment: %w", err)
+	}
+	return nil
+}
+
+// SegmentsByPomodoro returns segments of all sessions by session ID, each list ordered by start.
+func (s *Storage) SegmentsByPomodoro() (map[int][]*Segment, error) {
+	query := `SELECT id, pomodoro_id, start_at, finish_at, duration
+			FROM pomodoro_segments
+			ORDER BY start_at`
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("can't get segments: %w", err)
+	}
+	defer rows.Close()
+
+	segments := make(map[int][]*Segment)
+	for rows.Next() {
+		var segment Segment
+		err = rows.Scan(&segment.ID, &segment.PomodoroID, &segment.StartAt, &segment.FinishAt,
+			&segment.SecondsDuration)
+		if err != nil {
+			return nil, fmt.Errorf("can't scan segment: %w", err)
+		}
+		segments[segment.PomodoroID] = append(segments[segment.PomodoroID], &segment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+	return segments, nil
+}
+
 func (s *Storage) GetPomodoros() ([]*Pomodoro, error) {
-	query := `SELECT id, start_at, finish_at, duration
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
 			FROM pomodoros
+			WHERE kind = 'focus'
 			ORDER BY start_at DESC`
 	return s.fetchPomodoros(query)
 }
 
+// GetBreaks returns break sessions, they aren't part of other pomodoro queries.
+func (s *Storage) GetBreaks() ([]*Pomodoro, error) {
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
+			FROM pomodoros
+			WHERE kind = 'break'
+			ORDER BY start_at DESC`
+	return s.fetchPomodoros(query)
+}
+
+// GetSessions returns focus sessions and breaks together, the oldest first.
+func (s *Storage) GetSessions() ([]*Pomodoro, error) {
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
+			FROM pomodoros
+			ORDER BY start_at`
+	return s.fetchPomodoros(query)
+}
+
+// UnsyncedPomodoros returns finished focus sessions not pushed to service yet, the oldest first.
+// Abandoned sessions aren't synced: running session is stored as abandoned too.
+func (s *Storage) UnsyncedPomodoros(service string) ([]*Pomodoro, error) {
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
+			FROM pomodoros
+			WHERE kind = 'focus' AND outcome IN ('completed', 'skipped') AND duration > 0
+				AND id NOT IN (SELECT pomodoro_id FROM synced_entries WHERE service = ?)
+			ORDER BY start_at`
+	return s.fetchPomodoros(query, service)
+}
+
+// MarkSynced records that pomodoro is pushed to service as entry remoteID.
+func (s *Storage) MarkSynced(pomodoroID int, service, remoteID string) error {
+	query := `INSERT INTO synced_entries (pomodoro_id, service, remote_id) VALUES (?, ?, ?);`
+
+	_, err := s.DB.Exec(query, pomodoroID, service, remoteID)
+	if err != nil {
+		return fmt.Errorf("can't mark pomodoro synced: %w", err)
+	}
+	return nil
+}
+
 func (s *Storage) GetTodayPomodoros() ([]*Pomodoro, error) {
-	query := `SELECT id, start_at, finish_at, duration
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
 			FROM pomodoros
-			WHERE date(start_at) = current_date
+			WHERE kind = 'focus' AND date(start_at) = current_date
 			ORDER BY start_at DESC`
 
 	return s.fetchPomodoros(query)
 }
 
+// GetPomodorosBetween returns pomodoros started in [from, to) interval.
+func (s *Storage) GetPomodorosBetween(from, to time.Time) ([]*Pomodoro, error) {
+	query := `SELECT id, start_at, finish_at, duration, profile, adjusted, overtime, kind, outcome, task,
+			git_repo, git_branch
+			FROM pomodoros
+			WHERE kind = 'focus' AND datetime(start_at) >= datetime(?) AND datetime(start_at) < datetime(?)
+			ORDER BY start_at DESC`
+
+	return s.fetchPomodoros(query, from, to)
+}
+
 func (s *Storage) fetchPomodoros(query string, args ...any) ([]*Pomodoro, error) {
 	rows, err := s.DB.Query(query, args...)
 	if err != nil {
@@ -117,7 +294,9 @@ func (s *Storage) fetchPomodoros(query string, args ...any) ([]*Pomodoro, error)
 	for rows.Next() {
 		var pomodoro Pomodoro
 
-		err = rows.Scan(&pomodoro.ID, &pomodoro.StartAt, &pomodoro.FinishAt, &pomodoro.SecondsDuration)
+		err = rows.Scan(&pomodoro.ID, &pomodoro.StartAt, &pomodoro.FinishAt, &pomodoro.SecondsDuration,
+			&pomodoro.Profile, &pomodoro.AdjustedSeconds, &pomodoro.OvertimeSeconds, &pomodoro.Kind, &pomodoro.Outcome,
+			&pomodoro.Task, &pomodoro.GitRepo, &pomodoro.GitBranch)
 		if err != nil {
 			return nil, fmt.Errorf("can't scan pomodoro: %w", err)
 		}
@@ -127,12 +306,68 @@ func (s *Storage) fetchPomodoros(query string, args ...any) ([]*Pomodoro, error)
 	if err = rows.Err(); err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+
+	if err = s.countPauses(pomodoros); err != nil {
+		return nil, err
+	}
 	return pomodoros, nil
 }
 
+// countPauses fills pauses of pomodoros from their segments: each segment after the first one
+// follows a pause, paused time is the part of session span not covered by segments.
+func (s *Storage) countPauses(pomodoros []*Pomodoro) error {
+	if len(pomodoros) == 0 {
+		return nil
+	}
+
+	// segments are read for range of fetched IDs, unlike IN list it has no limit of query parameters
+	minID, maxID := pomodoros[0].ID, pomodoros[0].ID
+	for _, pomodoro := range pomodoros {
+		minID, maxID = min(minID, pomodoro.ID), max(maxID, pomodoro.ID)
+	}
+
+	query := `SELECT pomodoro_id, COUNT(*), SUM(duration) FROM pomodoro_segments
+			WHERE pomodoro_id BETWEEN ? AND ?
+			GROUP BY pomodoro_id`
+	rows, err := s.DB.Query(query, minID, maxID)
+	if err != nil {
+		return fmt.Errorf("can't count pauses: %w", err)
+	}
+	defer rows.Close()
+
+	type segments struct {
+		count  int
+		active int
+	}
+	byPomodoro := make(map[int]segments)
+
+	for rows.Next() {
+		var id int
+		var segs segments
+		if err = rows.Scan(&id, &segs.count, &segs.active); err != nil {
+			return fmt.Errorf("can't scan segments: %w", err)
+		}
+		byPomodoro[id] = segs
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	for _, pomodoro := range pomodoros {
+		segs, ok := byPomodoro[pomodoro.ID]
+		if !ok {
+			continue
+		}
+		span := int(pomodoro.FinishAt.Sub(pomodoro.StartAt).Seconds())
+		pomodoro.Pauses = segs.count - 1
+		pomodoro.PausedSeconds = max(span-segs.active, 0)
+	}
+	return nil
+}
+
 func (s *Storage) Tasks() ([]*Task, error) {
 	query := `SELECT id, name, pomodoros_required, pomodoros_completed,
-						is_complete, is_active, created_at
+						is_complete, is_active, created_at, COALESCE(uuid, ''), completed_at
 			  FROM tasks
 			  ORDER BY created_at;`
 	rows, err := s.DB.Query(query)
@@ -144,13 +379,17 @@ func (s *Storage) Tasks() ([]*Task, error) {
 	var tasks []*Task
 
 	for rows.Next() {
-		var task Task
+		var (
+			task       Task
+			completeAt sql.NullTime
+		)
 
 		err = rows.Scan(&task.ID, &task.Name, &task.PomodorosRequired, &task.PomodorosCompleted,
-			&task.IsComplete, &task.IsActive, &task.CreateAt)
+			&task.IsComplete, &task.IsActive, &task.CreateAt, &task.UUID, &completeAt)
 		if err != nil {
 			return nil, fmt.Errorf("can't scan task: %w", err)
 		}
+		task.CompleteAt = completeAt.Time
 		tasks = append(tasks, &task)
 	}
 
@@ -162,10 +401,18 @@ func (s *Storage) Tasks() ([]*Task, error) {
 }
 
 func (s *Storage) CreateTask(task *Task) error {
-	query := `INSERT INTO tasks (name, pomodoros_required, pomodoros_completed, is_complete, is_active)
-				VALUES (?, ?, ?, ?, ?)
+	// tasks without UUID keep NULL, unique index allows many of them
+	query := `INSERT INTO tasks (name, pomodoros_required, pomodoros_completed, is_complete, is_active, uuid,
+					created_at, completed_at)
+				VALUES (?, ?, ?, ?, ?, NULLIF(?, ''), COALESCE(?, current_timestamp),
+					CASE WHEN ? THEN current_timestamp END)
 				RETURNING id`
-	args := []any{task.Name, task.PomodorosRequired, task.PomodorosCompleted, task.IsComplete, task.IsActive}
+	var createdAt any
+	if !task.CreateAt.IsZero() {
+		createdAt = task.CreateAt.UTC()
+	}
+	args := []any{task.Name, task.PomodorosRequired, task.PomodorosCompleted, task.IsComplete, task.IsActive,
+		task.UUID, createdAt, task.IsComplete}
 
 	err := s.DB.QueryRow(query, args...).Scan(&task.ID)
 	if err != nil {
@@ -181,11 +428,11 @@ func (s *Storage) CreateTask(task *Task) error {
 	return nil
 }
 
-func (s *Storage) DeleteTask(id int) error {
+func (s *Storage) DeleteTask(task *Task) error {
 	query := `DELETE FROM tasks WHERE id = ?`
-	_, err := s.DB.Exec(query, id)
+	_, err := s.DB.Exec(query, task.ID)
 	if err != nil {
-		s.logger.Error("can't delete task", slog.Int("id", id))
+		s.logger.Error("can't delete task", slog.Int("id", task.ID))
 		return fmt.Errorf("can't delete task: %w", err)
 	}
 	return nil
@@ -193,9 +440,11 @@ func (s *Storage) DeleteTask(id int) error {
 
 func (s *Storage) UpdateTask(task *Task) error {
 	query := `UPDATE tasks 
-				SET name = ?, pomodoros_required = ?, pomodoros_completed = ?, is_complete = ?, is_active = ?
+				SET name = ?, pomodoros_required = ?, pomodoros_completed = ?, is_complete = ?, is_active = ?,
+					uuid = NULLIF(?, ''), completed_at = CASE WHEN ? THEN COALESCE(completed_at, current_timestamp) END
 				WHERE id = ?;`
-	args := []any{task.Name, task.PomodorosRequired, task.PomodorosCompleted, task.IsComplete, task.IsActive, task.ID}
+	args := []any{task.Name, task.PomodorosRequired, task.PomodorosCompleted, task.IsComplete, task.IsActive,
+		task.UUID, task.IsComplete, task.ID}
 	_, err := s.DB.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("can't upate task: %w", err)
@@ -204,12 +453,13 @@ func (s *Storage) UpdateTask(task *Task) error {
 }
 
 func (s *Storage) ActiveTask() (*Task, error) {
-	query := `SELECT id, name, pomodoros_required, pomodoros_completed, is_complete, is_active, created_at
+	query := `SELECT id, name, pomodoros_required, pomodoros_completed, is_complete, is_active, created_at,
+					COALESCE(uuid, '')
 				FROM tasks
 			  WHERE is_active = true;`
 	var task Task
 	err := s.DB.QueryRow(query).Scan(&task.ID, &task.Name, &task.PomodorosRequired, &task.PomodorosCompleted,
-		&task.IsComplete, &task.IsActive, &task.CreateAt)
+		&task.IsComplete, &task.IsActive, &task.CreateAt, &task.UUID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			s.logger.Warn("query active task, when active task not exist")
@@ -220,6 +470,16 @@ func (s *Storage) ActiveTask() (*Task, error) {
 	return &task, nil
 }
 
+// ActiveTaskName returns name of active task, empty if there is none.
+func (s *Storage) ActiveTaskName() (string, error) {
+	var name string
+	err := s.DB.QueryRow(`SELECT name FROM tasks WHERE is_active = true;`).Scan(&name)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("can't find active task: %w", err)
+	}
+	return name, nil
+}
+
 func (s *Storage) IncPomodoroActiveTask() error {
 	query := `UPDATE tasks
     			SET
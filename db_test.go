@@ -47,22 +47,34 @@ func setup() error {
 
 func TestStorage_CreatePomodoro(t *testing.T) {
 	pomodoro := &Pomodoro{
-		ID:       100,
-		StartAt:  time.Now(),
-		FinishAt: time.Now(),
+		ID:        100,
+		StartAt:   time.Now(),
+		FinishAt:  time.Now(),
+		Profile:   "deep work",
+		Task:      "write report",
+		GitRepo:   "/home/user/pomotrack",
+		GitBranch: "main",
 	}
 	err := s.CreatePomodoro(pomodoro)
 	require.NoError(t, err)
 
 	var pomdoroFromDB Pomodoro
 	err = s.DB.QueryRow(`SELECT * FROM pomodoros;`).Scan(&pomdoroFromDB.ID, &pomdoroFromDB.StartAt,
-		&pomdoroFromDB.FinishAt, &pomdoroFromDB.SecondsDuration)
+		&pomdoroFromDB.FinishAt, &pomdoroFromDB.SecondsDuration, &pomdoroFromDB.Profile,
+		&pomdoroFromDB.AdjustedSeconds, &pomdoroFromDB.OvertimeSeconds, &pomdoroFromDB.Kind, &pomdoroFromDB.Outcome,
+		&pomdoroFromDB.Task, &pomdoroFromDB.GitRepo, &pomdoroFromDB.GitBranch)
 	require.NoError(t, err)
 
 	require.Equal(t, pomodoro.ID, pomdoroFromDB.ID)
 	require.WithinDuration(t, pomodoro.StartAt, pomdoroFromDB.StartAt, time.Second)
 	require.WithinDuration(t, pomodoro.FinishAt, pomdoroFromDB.FinishAt, time.Second)
 	require.Equal(t, pomodoro.SecondsDuration, pomdoroFromDB.SecondsDuration)
+	require.Equal(t, pomodoro.Profile, pomdoroFromDB.Profile)
+	require.Equal(t, pomodoro.Task, pomdoroFromDB.Task)
+	require.Equal(t, pomodoro.GitRepo, pomdoroFromDB.GitRepo)
+	require.Equal(t, pomodoro.GitBranch, pomdoroFromDB.GitBranch)
+	require.Equal(t, SessionFocus, pomdoroFromDB.Kind)
+	require.Equal(t, OutcomeCompleted, pomdoroFromDB.Outcome)
 
 	clearTable()
 }
@@ -78,19 +90,27 @@ func TestStorage_UpdatePomodoro(t *testing.T) {
 
 	pomodoro.FinishAt = time.Now().Add(60 * time.Second)
 	pomodoro.SecondsDuration = 60
+	pomodoro.AdjustedSeconds = -60
+	pomodoro.OvertimeSeconds = 30
+	pomodoro.Outcome = OutcomeSkipped
 
 	err = s.UpdatePomodoro(pomodoro)
 	require.NoError(t, err)
 
 	var pomodoroFromDB Pomodoro
 	err = s.DB.QueryRow(`SELECT * FROM pomodoros;`).Scan(&pomodoroFromDB.ID, &pomodoroFromDB.StartAt,
-		&pomodoroFromDB.FinishAt, &pomodoroFromDB.SecondsDuration)
+		&pomodoroFromDB.FinishAt, &pomodoroFromDB.SecondsDuration, &pomodoroFromDB.Profile,
+		&pomodoroFromDB.AdjustedSeconds, &pomodoroFromDB.OvertimeSeconds, &pomodoroFromDB.Kind,
+		&pomodoroFromDB.Outcome, &pomodoroFromDB.Task, &pomodoroFromDB.GitRepo, &pomodoroFromDB.GitBranch)
 	require.NoError(t, err)
 
 	require.Equal(t, pomodoro.ID, pomodoroFromDB.ID)
 	require.WithinDuration(t, pomodoro.StartAt, pomodoroFromDB.StartAt, time.Second)
 	require.WithinDuration(t, pomodoro.FinishAt, pomodoroFromDB.FinishAt, time.Second)
 	require.Equal(t, pomodoro.SecondsDuration, pomodoroFromDB.SecondsDuration)
+	require.Equal(t, pomodoro.AdjustedSeconds, pomodoroFromDB.AdjustedSeconds)
+	require.Equal(t, pomodoro.OvertimeSeconds, pomodoroFromDB.OvertimeSeconds)
+	require.Equal(t, OutcomeSkipped, pomodoroFromDB.Outcome)
 
 	clearTable()
 }
@@ -222,7 +242,8 @@ func TestStorage_CreateTask(t *testing.T) {
 		require.NotEqualf(t, 100, task.ID, "autoincrement not working")
 	}
 
-	rows, err := s.DB.Query(`SELECT * FROM tasks;`)
+	rows, err := s.DB.Query(`SELECT id, name, pomodoros_required, pomodoros_completed, is_complete, is_active,
+		created_at FROM tasks;`)
 	require.NoError(t, err)
 	defer rows.Close()
 
@@ -352,5 +373,121 @@ func randomTimestamp() time.Time {
 
 func clearTable() {
 	s.DB.Exec(`DELETE FROM pomodoros;`)
+	s.DB.Exec(`DELETE FROM pomodoro_segments;`)
 	s.DB.Exec(`DELETE FROM TASKS`)
+	s.DB.Exec(`DELETE FROM synced_entries;`)
+}
+
+func TestStorage_GetPomodorosBetween(t *testing.T) {
+	day := time.Date(2024, time.October, 7, 23, 30, 0, 0, time.Local)
+
+	for i := range 5 {
+		pomodoro := &Pomodoro{
+			StartAt:  day.AddDate(0, 0, i),
+			FinishAt: day.AddDate(0, 0, i),
+		}
+		err := s.CreatePomodoro(pomodoro)
+		require.NoError(t, err)
+	}
+
+	from := time.Date(2024, time.October, 8, 0, 0, 0, 0, time.Local)
+	pomodoros, err := s.GetPomodorosBetween(from, from.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, pomodoros, 2)
+
+	for _, pomodoro := range pomodoros {
+		assert.True(t, pomodoro.StartAt.After(from))
+	}
+
+	clearTable()
+}
+
+func TestStorage_GetBreaks(t *testing.T) {
+	for _, kind := range []SessionKind{SessionFocus, SessionBreak, SessionBreak} {
+		pomodoro := &Pomodoro{
+			StartAt:  time.Now(),
+			FinishAt: time.Now(),
+			Kind:     kind,
+		}
+		err := s.CreatePomodoro(pomodoro)
+		require.NoError(t, err)
+	}
+
+	breaks, err := s.GetBreaks()
+	require.NoError(t, err)
+	require.Len(t, breaks, 2)
+
+	pomodoros, err := s.GetPomodoros()
+	require.NoError(t, err)
+	require.Len(t, pomodoros, 1)
+
+	clearTable()
+}
+
+func TestStorage_Segments(t *testing.T) {
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)
+	pomodoro := &Pomodoro{
+		StartAt:         start,
+		FinishAt:        start.Add(30 * time.Minute),
+		SecondsDuration: 25 * 60,
+	}
+	require.NoError(t, s.CreatePomodoro(pomodoro))
+
+	segments := []*Segment{
+		{PomodoroID: pomodoro.ID, StartAt: start, FinishAt: start.Add(10 * time.Minute), SecondsDuration: 10 * 60},
+		{PomodoroID: pomodoro.ID, StartAt: start.Add(15 * time.Minute), FinishAt: start.Add(30 * time.Minute),
+			SecondsDuration: 15 * 60},
+	}
+	for _, segment := range segments {
+		require.NoError(t, s.CreateSegment(segment))
+	}
+
+	pomodoros, err := s.GetPomodoros()
+	require.NoError(t, err)
+	require.Len(t, pomodoros, 1)
+	assert.Equal(t, 1, pomodoros[0].Pauses)
+	assert.Equal(t, 5*60, pomodoros[0].PausedSeconds)
+
+	byPomodoro, err := s.SegmentsByPomodoro()
+	require.NoError(t, err)
+	require.Len(t, byPomodoro[pomodoro.ID], 2)
+	assert.Equal(t, 15*60, byPomodoro[pomodoro.ID][1].SecondsDuration)
+
+	require.NoError(t, s.RemovePomodoro(pomodoro.ID))
+	var count int
+	require.NoError(t, s.DB.QueryRow(`SELECT COUNT(*) FROM pomodoro_segments`).Scan(&count))
+	assert.Zero(t, count)
+
+	clearTable()
+}
+
+func TestStorage_SubtractFromPomodoro(t *testing.T) {
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)
+	pomodoro := &Pomodoro{
+		StartAt:         start,
+		FinishAt:        start.Add(30 * time.Minute),
+		SecondsDuration: 25 * 60,
+	}
+	require.NoError(t, s.CreatePomodoro(pomodoro))
+	require.NoError(t, s.CreateSegment(&Segment{PomodoroID: pomodoro.ID, StartAt: start,
+		FinishAt: start.Add(10 * time.Minute), SecondsDuration: 10 * 60}))
+	require.NoError(t, s.CreateSegment(&Segment{PomodoroID: pomodoro.ID, StartAt: start.Add(15 * time.Minute),
+		FinishAt: start.Add(30 * time.Minute), SecondsDuration: 15 * 60}))
+
+	require.NoError(t, s.SubtractFromPomodoro(pomodoro.ID, 5*60))
+
+	pomodoros, err := s.GetPomodoros()
+	require.NoError(t, err)
+	require.Len(t, pomodoros, 1)
+	assert.Equal(t, 20*60, pomodoros[0].SecondsDuration)
+	assert.True(t, start.Add(25*time.Minute).Equal(pomodoros[0].FinishAt))
+
+	byPomodoro, err := s.SegmentsByPomodoro()
+	require.NoError(t, err)
+	assert.Equal(t, 10*60, byPomodoro[pomodoro.ID][1].SecondsDuration)
+	assert.True(t, start.Add(25*time.Minute).Equal(byPomodoro[pomodoro.ID][1].FinishAt))
+
+	require.ErrorIs(t, s.SubtractFromPomodoro(pomodoro.ID+1, 60), errNoFinishedPomodoro)
+
+	clearTable()
 }
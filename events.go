@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sync"
+)
+
+const subscriberBufferSize = 16
+
+// EventBroker fans out timer state events to any number of subscribers
+// (HTTP streams) without blocking the state machine.
+type EventBroker struct {
+	mu          sync.RWMutex
+	subscribers map[chan StateEvent]struct{}
+}
+
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		mu:          sync.RWMutex{},
+		subscribers: make(map[chan StateEvent]struct{}),
+	}
+}
+
+// Subscribe returns channel with state events and function to stop receiving them.
+func (b *EventBroker) Subscribe() (<-chan StateEvent, func()) {
+	ch := make(chan StateEvent, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+		b.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+// Publish sends event to every subscriber, slow subscribers miss the event.
+func (b *EventBroker) Publish(event StateEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
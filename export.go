@@ -0,0 +1,126 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+// calendarFileName is the file written by export from the detail statistics page.
+const calendarFileName = "pomotrack.ics"
+
+var errUnknownFormat = errors.New("unknown export format")
+
+// runExport handles "pomotrack export": writes stored sessions to file or stdout.
+func runExport(logger *slog.Logger, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", "ics", "export format: ics, org")
+	output := flags.String("output", "", "write to file instead of stdout")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("can't parse export flags: %w", err)
+	}
+	// existing file isn't touched by export that fails anyway
+	if err := checkExportFormat(*format); err != nil {
+		return err
+	}
+
+	storage, err := openStorage(logger)
+	if err != nil {
+		return err
+	}
+	defer storage.DB.Close()
+
+	if *output == "" {
+		return exportSessions(storage, os.Stdout, *format)
+	}
+	return writeFile(*output, func(w io.Writer) error {
+		return exportSessions(storage, w, *format)
+	})
+}
+
+// writeFile creates file and fills it with write. Error of close is returned too,
+// failed write may show up only there.
+func writeFile(path string, write func(w io.Writer) error) (err error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("can't create export file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("can't close export file: %w", closeErr)
+		}
+	}()
+
+	return write(file)
+}
+
+// checkExportFormat tells if sessions can be exported in format.
+func checkExportFormat(format string) error {
+	switch format {
+	case "ics", "org":
+		return nil
+	}
+	return fmt.Errorf("%w: %q", errUnknownFormat, format)
+}
+
+// openStorage opens app database for commands run without UI.
+func openStorage(logger *slog.Logger) (*Storage, error) {
+	storage, err := NewStorage(databaseName, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = storage.Migrate(); err != nil {
+		storage.DB.Close()
+		return nil, err
+	}
+	return storage, nil
+}
+
+// exportSessions writes finished sessions, focus and breaks, in given format.
+func exportSessions(storage *Storage, w io.Writer, format string) error {
+	if err := checkExportFormat(format); err != nil {
+		return err
+	}
+
+	sessions, err := storage.GetSessions()
+	if err != nil {
+		return err
+	}
+
+	finished := make([]*Pomodoro, 0, len(sessions))
+	for _, session := range sessions {
+		if session.counted() && session.FinishAt.After(session.StartAt) {
+			finished = append(finished, session)
+		}
+	}
+
+	if format == "org" {
+		segments, err := storage.SegmentsByPomodoro()
+		if err != nil {
+			return err
+		}
+		return writeOrg(w, finished, segments)
+	}
+	return writeICS(w, finished, time.Now())
+}
+
+// exportCalendar writes calendar file next to config and returns its path.
+func exportCalendar(storage *Storage) (string, error) {
+	path := filepath.Join(config.GetConfigDir(), calendarFileName)
+
+	err := writeFile(path, func(w io.Writer) error {
+		return exportSessions(storage, w, "ics")
+	})
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
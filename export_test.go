@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunExport_UnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cal.ics")
+	require.NoError(t, os.WriteFile(path, []byte("BEGIN:VCALENDAR"), 0o600))
+
+	err := runExport(slog.Default(), []string{"--format", "bogus", "--output", path})
+	require.ErrorIs(t, err, errUnknownFormat)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "BEGIN:VCALENDAR", string(data))
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const shortHashLength = 7
+
+// gitHead returns root of git repository dir belongs to and its checked out branch, or short commit hash
+// if HEAD is detached. Files of .git are read directly, both values are empty outside of repository.
+func gitHead(dir string) (string, string) {
+	root, gitDir := findGitDir(dir)
+	if root == "" {
+		return "", ""
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return root, ""
+	}
+	head := strings.TrimSpace(string(data))
+
+	if ref, ok := strings.CutPrefix(head, "ref:"); ok {
+		return root, strings.TrimPrefix(strings.TrimSpace(ref), "refs/heads/")
+	}
+	if len(head) > shortHashLength {
+		head = head[:shortHashLength]
+	}
+	return root, head
+}
+
+// findGitDir walks up from dir to directory with .git. In worktrees and submodules .git is a file
+// with path to git directory, e.g. "gitdir: ../.git/worktrees/feature".
+func findGitDir(dir string) (string, string) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		info, err := os.Stat(gitPath)
+		switch {
+		case err == nil && info.IsDir():
+			return dir, gitPath
+		case err == nil:
+			data, err := os.ReadFile(gitPath)
+			if err != nil {
+				return "", ""
+			}
+			gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+			if !ok {
+				return "", ""
+			}
+			gitDir = strings.TrimSpace(gitDir)
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			return dir, gitDir
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", ""
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHead(t *testing.T) {
+	root := t.TempDir()
+	gitDir := filepath.Join(root, ".git")
+	subDir := filepath.Join(root, "cmd", "app")
+	require.NoError(t, os.MkdirAll(gitDir, 0o700))
+	require.NoError(t, os.MkdirAll(subDir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/feature/git\n"), 0o600))
+
+	repo, branch := gitHead(subDir)
+	assert.Equal(t, root, repo)
+	assert.Equal(t, "feature/git", branch)
+
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"),
+		[]byte("3f2a9c1d5e7b8a0f4c6d2e1b9a8c7d6e5f4a3b2c\n"), 0o600))
+	_, branch = gitHead(root)
+	assert.Equal(t, "3f2a9c1", branch)
+}
+
+func TestGitHead_Worktree(t *testing.T) {
+	root := t.TempDir()
+	gitDir := filepath.Join(root, "main", ".git", "worktrees", "fix")
+	worktree := filepath.Join(root, "fix")
+	require.NoError(t, os.MkdirAll(gitDir, 0o700))
+	require.NoError(t, os.MkdirAll(worktree, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/hotfix\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(worktree, ".git"),
+		[]byte("gitdir: ../main/.git/worktrees/fix\n"), 0o600))
+
+	repo, branch := gitHead(worktree)
+	assert.Equal(t, worktree, repo)
+	assert.Equal(t, "hotfix", branch)
+}
+
+func TestGitHead_NoRepository(t *testing.T) {
+	repo, branch := gitHead(t.TempDir())
+	assert.Empty(t, repo)
+	assert.Empty(t, branch)
+}
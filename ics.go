@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	icsTimeFormat = "20060102T150405Z"
+	// icsLineLimit is the longest content line in octets, longer lines are folded.
+	icsLineLimit = 75
+)
+
+// writeICS writes sessions as iCalendar events. UID of event is built from session ID,
+// so calendar updates events on re-import instead of adding copies. Times are in UTC.
+func writeICS(w io.Writer, sessions []*Pomodoro, now time.Time) error {
+	buf := bufio.NewWriter(w)
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//PomoTrack//PomoTrack//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:PomoTrack",
+	}
+	for _, session := range sessions {
+		lines = append(lines, icsEvent(session, now)...)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := buf.WriteString(foldICSLine(line)); err != nil {
+			return fmt.Errorf("can't write calendar: %w", err)
+		}
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("can't write calendar: %w", err)
+	}
+	return nil
+}
+
+func icsEvent(session *Pomodoro, now time.Time) []string {
+	description := fmt.Sprintf("%s, %d min, %s", session.Kind, session.SecondsDuration/60, session.Outcome)
+	if session.Profile != "" {
+		description += ", profile " + session.Profile
+	}
+	if session.GitRepo != "" {
+		description += ", git " + gitLabel(session)
+	}
+
+	return []string{
+		"BEGIN:VEVENT",
+		fmt.Sprintf("UID:pomodoro-%d@pomotrack", session.ID),
+		"DTSTAMP:" + now.UTC().Format(icsTimeFormat),
+		"DTSTART:" + session.StartAt.UTC().Format(icsTimeFormat),
+		"DTEND:" + session.FinishAt.UTC().Format(icsTimeFormat),
+		"SUMMARY:" + escapeICSText(sessionSummary(session)),
+		"DESCRIPTION:" + escapeICSText(description),
+		"CATEGORIES:" + strings.ToUpper(string(session.Kind)),
+		"TRANSP:OPAQUE",
+		"END:VEVENT",
+	}
+}
+
+// gitLabel names repository of session by its directory, e.g. "pomotrack/main".
+func gitLabel(session *Pomodoro) string {
+	label := filepath.Base(session.GitRepo)
+	if session.GitBranch != "" {
+		label += "/" + session.GitBranch
+	}
+	return label
+}
+
+// sessionSummary names session by its task, session without task by its kind.
+func sessionSummary(session *Pomodoro) string {
+	switch {
+	case session.Kind == SessionBreak:
+		return "Break"
+	case session.Task != "":
+		return session.Task
+	}
+	return "Focus"
+}
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
+
+// foldICSLine splits line longer than the limit into CRLF separated parts,
+// each continuation starts with a space. Multibyte characters aren't split.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > icsLineLimit {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteICS(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, moscow)
+	sessions := []*Pomodoro{
+		{ID: 7, StartAt: start, FinishAt: start.Add(25 * time.Minute), SecondsDuration: 25 * 60,
+			Kind: SessionFocus, Outcome: OutcomeCompleted, Task: "Read; write, repeat",
+			GitRepo: "/home/user/pomotrack", GitBranch: "main"},
+		{ID: 8, StartAt: start.Add(25 * time.Minute), FinishAt: start.Add(30 * time.Minute), SecondsDuration: 5 * 60,
+			Kind: SessionBreak, Outcome: OutcomeSkipped},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeICS(&buf, sessions, start))
+	calendar := buf.String()
+
+	assert.True(t, strings.HasPrefix(calendar, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(calendar, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(calendar, "BEGIN:VEVENT"))
+
+	assert.Contains(t, calendar, "UID:pomodoro-7@pomotrack\r\n")
+	assert.Contains(t, calendar, "DTSTART:20241007T070000Z\r\n")
+	assert.Contains(t, calendar, "DTEND:20241007T072500Z\r\n")
+	assert.Contains(t, calendar, `SUMMARY:Read\; write\, repeat`+"\r\n")
+	assert.Contains(t, calendar, "SUMMARY:Break\r\n")
+	assert.Contains(t, calendar, `DESCRIPTION:focus\, 25 min\, completed\, git pomotrack/main`+"\r\n")
+}
+
+func TestFoldICSLine(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("помидор ", 20)
+	folded := foldICSLine(line)
+
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(part), icsLineLimit)
+	}
+	assert.Equal(t, line, strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", ""))
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	keyTipsWidth = 30
+	helpPage     = "Help"
+	helpWidth    = 60
+)
+
+func (m *UIManager) formatKeyHints(hints []keyHint) string {
+	lines := make([]string, 0, len(hints))
+	for _, hint := range hints {
+		lines = append(lines, fmt.Sprintf("%s %s", m.theme.Highlight.Wrap(tview.Escape(hint.key)), hint.description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// constructKeyTips returns sidebar for page or nil if key tips are hidden.
+func (m *UIManager) constructKeyTips(page PageName) tview.Primitive {
+	if !m.showKeyTips {
+		return nil
+	}
+	return tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(m.formatKeyHints(m.keys.Help(page)))
+}
+
+// toggleKeyTips shows or hides sidebar and redraws current page.
+func (m *UIManager) toggleKeyTips() {
+	m.showKeyTips = !m.showKeyTips
+	if m.currentPage != nil {
+		m.AddPageAndSwitch(m.currentPage)
+	}
+}
+
+// helpText describes keys of every page.
+func (m *UIManager) helpText() string {
+	sections := []struct {
+		title string
+		page  PageName
+	}{
+		{"Timer on pause", pauseFocusPage},
+		{"Running timer", activeFocusPage},
+		{"Flowtime", activeStopwatchPage},
+		{"Tasks", allTasksPage},
+		{"Forms and settings", addNewTaskPage},
+		{"Detail statistics", detailStatsPage},
+	}
+
+	var sb strings.Builder
+	sb.WriteString(m.theme.PageActive.Wrap(" Global ") + "\n")
+	sb.WriteString(m.formatKeyHints(m.keys.GlobalHelp()))
+	for _, section := range sections {
+		sb.WriteString("\n\n" + m.theme.PageActive.Wrap(" "+section.title+" ") + "\n")
+		sb.WriteString(m.formatKeyHints(m.keys.PageHelp(section.page)))
+	}
+	return sb.String()
+}
+
+// toggleHelp opens help overlay over current page or closes it.
+func (m *UIManager) toggleHelp() {
+	if m.pages.HasPage(helpPage) {
+		m.closeHelp()
+		return
+	}
+
+	text := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(m.helpText())
+	text.SetBorder(true).
+		SetTitle(fmt.Sprintf(" Help (%s/Esc to close) ", tview.Escape(m.keys.Name(actionHelp))))
+	text.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape {
+			m.closeHelp()
+			return nil
+		}
+		return event
+	})
+
+	overlay := tview.NewGrid().
+		SetRows(1, 0, 1).
+		SetColumns(0, helpWidth, 0).
+		AddItem(text, 1, 1, 1, 1, 0, 0, true)
+
+	m.pages.AddPage(helpPage, overlay, true, true)
+	m.ui.SetFocus(text)
+}
+
+func (m *UIManager) closeHelp() {
+	m.closeOverlay(helpPage)
+}
+
+// closeOverlay removes page shown over current one and returns focus back.
+func (m *UIManager) closeOverlay(name string) {
+	if !m.pages.HasPage(name) {
+		return
+	}
+	m.pages.RemovePage(name)
+	if _, front := m.pages.GetFrontPage(); front != nil {
+		m.ui.SetFocus(front)
+	}
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type Action string
+
+const (
+	actionStartPause   Action = "start_pause"
+	actionSkip         Action = "skip"
+	actionFocusPage    Action = "focus_page"
+	actionBreakPage    Action = "break_page"
+	actionFlowtimePage Action = "flowtime_page"
+	actionTasksPage    Action = "tasks_page"
+	actionSummaryPage  Action = "summary_page"
+	actionDetailPage   Action = "detail_page"
+	actionAddTask      Action = "add_task"
+	actionDeleteTask   Action = "delete_task"
+	actionInsertRecord Action = "insert_record"
+	actionDeleteRecord Action = "delete_record"
+	actionToggleTips   Action = "toggle_key_tips"
+	actionHelp         Action = "help"
+	actionSettingsPage Action = "settings_page"
+	actionNextProfile  Action = "next_profile"
+	actionStartPlan    Action = "start_plan"
+	actionAbortPlan    Action = "abort_plan"
+	actionAddMinute    Action = "add_minute"
+	actionAddFive      Action = "add_five_minutes"
+	actionRemoveMinute Action = "remove_minute"
+	actionExportICS    Action = "export_calendar"
+
+	// keys of widgets and page input captures, they can't be rebound
+	actionQuit          Action = "quit"
+	actionStartTimer    Action = "start_timer"
+	actionSwitchButton  Action = "switch_button"
+	actionPressButton   Action = "press_button"
+	actionSelectTask    Action = "select_task"
+	actionJumpToTask    Action = "jump_to_task"
+	actionActivateTask  Action = "activate_task"
+	actionNextField     Action = "next_field"
+	actionPrevField     Action = "previous_field"
+	actionFocusButtons  Action = "focus_buttons"
+	actionSelectRecords Action = "select_records"
+	actionMoveSelection Action = "move_selection"
+	actionSelectAction  Action = "select_action"
+	actionDropSelection Action = "drop_selection"
+)
+
+var (
+	errUnknownAction  = errors.New("unknown action")
+	errInvalidKeySpec = errors.New("invalid key spec")
+	errKeyConflict    = errors.New("key is bound to several actions")
+)
+
+type actionInfo struct {
+	action      Action
+	defaultKey  string
+	description string
+	// pages where action works, nil means everywhere
+	pages []PageName
+	// fixed action is handled by widget or has key hardcoded, it's only shown in help
+	fixed bool
+}
+
+// allActions returns every action in the order it's shown to user.
+func allActions() []actionInfo {
+	timerPages := []PageName{pauseFocusPage, activeFocusPage, pauseBreakPage, activeBreakPage}
+	pausePages := []PageName{pauseFocusPage, pauseBreakPage}
+	stopwatchPages := []PageName{pauseStopwatchPage, activeStopwatchPage}
+	activePages := []PageName{activeFocusPage, activeBreakPage, activeStopwatchPage}
+	allPausePages := []PageName{pauseFocusPage, pauseBreakPage, pauseStopwatchPage}
+	formPages := []PageName{addNewTaskPage, deleteTaskPage, insertStatsPage, settingsPage}
+	detailPages := []PageName{detailStatsPage}
+	tasksPages := []PageName{allTasksPage}
+
+	return []actionInfo{
+		{actionFocusPage, "F1", "Focus", nil, false},
+		{actionBreakPage, "F2", "Break", nil, false},
+		{actionFlowtimePage, "F7", "Flowtime", nil, false},
+		{actionTasksPage, "F3", "Tasks", nil, false},
+		{actionSummaryPage, "F4", "Summary", nil, false},
+		{actionDetailPage, "F5", "Detail", nil, false},
+		{actionSettingsPage, "F6", "Settings", nil, false},
+		{actionToggleTips, "F9", "Show/hide key tips", nil, false},
+		{actionHelp, "?", "Help", nil, false},
+		{actionQuit, "Ctrl+C", "Quit", nil, true},
+		{actionStartTimer, "Enter", "Start timer", allPausePages, true},
+		{actionSwitchButton, "Tab/←/→", "Switch button", activePages, true},
+		{actionPressButton, "Enter", "Press button", append(activePages, formPages...), true},
+		{actionSelectTask, "↑/↓", "Select task", tasksPages, true},
+		{actionJumpToTask, "0-9", "Jump to task", tasksPages, true},
+		{actionActivateTask, "Enter", "Make task active", tasksPages, true},
+		{actionNextField, "Tab", "Next field", formPages, true},
+		{actionPrevField, "Shift+Tab", "Previous field", formPages, true},
+		{actionFocusButtons, "Tab", "Focus page buttons", detailPages, true},
+		{actionSelectRecords, "Enter", "Select records", detailPages, true},
+		{actionMoveSelection, "↑/↓", "Move selection", detailPages, true},
+		{actionSelectAction, "←/→", "Select action", detailPages, true},
+		{actionDropSelection, "Esc", "Drop selection", detailPages, true},
+		{actionStartPause, "Space", "Start/pause timer", append(stopwatchPages, timerPages...), false},
+		{actionSkip, "n", "Finish timer", activePages, false},
+		{actionNextProfile, "p", "Next timer profile", pausePages, false},
+		{actionStartPlan, "Ctrl+P", "Start session plan", pausePages, false},
+		{actionAbortPlan, "Ctrl+X", "Abort session plan", timerPages, false},
+		{actionAddMinute, "+", "Add 1 minute", timerPages, false},
+		{actionAddFive, "5", "Add 5 minutes", timerPages, false},
+		{actionRemoveMinute, "-", "Remove 1 minute", timerPages, false},
+		{actionAddTask, "Ctrl+A", "Create task", tasksPages, false},
+		{actionDeleteTask, "Ctrl+D", "Delete task", tasksPages, false},
+		{actionInsertRecord, "Ctrl+A", "Create record", detailPages, false},
+		{actionDeleteRecord, "Ctrl+Y", "Delete selected record", detailPages, false},
+		{actionExportICS, "Ctrl+E", "Export calendar (.ics)", detailPages, false},
+	}
+}
+
+// navigationActions maps page switching actions to target pages.
+func navigationActions() map[Action]PageName {
+	return map[Action]PageName{
+		actionFocusPage:    pauseFocusPage,
+		actionBreakPage:    pauseBreakPage,
+		actionFlowtimePage: pauseStopwatchPage,
+		actionTasksPage:    allTasksPage,
+		actionSummaryPage:  summaryStatsPage,
+		actionDetailPage:   detailStatsPage,
+		actionSettingsPage: settingsPage,
+	}
+}
+
+type KeySpec struct {
+	key  tcell.Key
+	ch   rune
+	mod  tcell.ModMask
+	name string
+}
+
+type KeyBindings struct {
+	specs map[Action]KeySpec
+}
+
+// NewKeyBindings creates bindings from defaults and user overrides (action -> key spec).
+func NewKeyBindings(overrides map[string]string) (*KeyBindings, error) {
+	kb := &KeyBindings{specs: make(map[Action]KeySpec)}
+	actions := allActions()
+
+	known := make(map[Action]bool, len(actions))
+	for _, info := range actions {
+		known[info.action] = !info.fixed
+	}
+	for action := range overrides {
+		if !known[Action(action)] {
+			return nil, fmt.Errorf("%w: %s", errUnknownAction, action)
+		}
+	}
+
+	for _, info := range actions {
+		if info.fixed {
+			continue
+		}
+		spec := info.defaultKey
+		if override, ok := overrides[string(info.action)]; ok {
+			spec = override
+		}
+		keySpec, err := ParseKeySpec(spec)
+		if err != nil {
+			return nil, fmt.Errorf("action %s: %w", info.action, err)
+		}
+		kb.specs[info.action] = keySpec
+	}
+
+	if err := kb.checkConflicts(actions); err != nil {
+		return nil, err
+	}
+	return kb, nil
+}
+
+// checkConflicts reports actions with the same key that can be triggered on the same page.
+func (kb *KeyBindings) checkConflicts(actions []actionInfo) error {
+	for i, a := range actions {
+		for _, b := range actions[i+1:] {
+			if a.fixed || b.fixed || kb.specs[a.action] != kb.specs[b.action] || !pagesOverlap(a.pages, b.pages) {
+				continue
+			}
+			return fmt.Errorf("%w: %s (%s, %s)", errKeyConflict, kb.specs[a.action].name, a.action, b.action)
+		}
+	}
+	return nil
+}
+
+func pagesOverlap(a, b []PageName) bool {
+	if a == nil || b == nil {
+		return true
+	}
+	for _, page := range a {
+		if containPage(page, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match checks if key event triggers action.
+func (kb *KeyBindings) Match(action Action, event *tcell.EventKey) bool {
+	spec, ok := kb.specs[action]
+	if !ok {
+		return false
+	}
+	return spec.Match(event)
+}
+
+// Name returns key name for action to show in UI.
+func (kb *KeyBindings) Name(action Action) string {
+	return kb.specs[action].name
+}
+
+// Overrides returns keys that differ from defaults, in the config format.
+func (kb *KeyBindings) Overrides() map[string]string {
+	overrides := make(map[string]string)
+	for _, info := range allActions() {
+		if info.fixed {
+			continue
+		}
+		defaultSpec, err := ParseKeySpec(info.defaultKey)
+		if err != nil || defaultSpec != kb.specs[info.action] {
+			overrides[string(info.action)] = kb.Name(info.action)
+		}
+	}
+	return overrides
+}
+
+type keyHint struct {
+	key         string
+	description string
+}
+
+// Help returns hints for actions available on page.
+func (kb *KeyBindings) Help(page PageName) []keyHint {
+	return append(kb.PageHelp(page), kb.GlobalHelp()...)
+}
+
+// PageHelp returns hints for actions that work only on page.
+func (kb *KeyBindings) PageHelp(page PageName) []keyHint {
+	hints := make([]keyHint, 0)
+	for _, info := range allActions() {
+		if info.pages != nil && containPage(page, info.pages) {
+			hints = append(hints, kb.hint(info))
+		}
+	}
+	return hints
+}
+
+// GlobalHelp returns hints for actions that work on every page.
+func (kb *KeyBindings) GlobalHelp() []keyHint {
+	hints := make([]keyHint, 0)
+	for _, info := range allActions() {
+		if info.pages == nil {
+			hints = append(hints, kb.hint(info))
+		}
+	}
+	return hints
+}
+
+func (kb *KeyBindings) hint(info actionInfo) keyHint {
+	key := info.defaultKey
+	if !info.fixed {
+		key = kb.Name(info.action)
+	}
+	return keyHint{key: key, description: info.description}
+}
+
+func (k KeySpec) Match(event *tcell.EventKey) bool {
+	const modifiers = tcell.ModCtrl | tcell.ModAlt
+
+	if k.key == tcell.KeyRune {
+		return event.Key() == tcell.KeyRune && event.Rune() == k.ch &&
+			event.Modifiers()&tcell.ModAlt == k.mod&tcell.ModAlt
+	}
+	if event.Key() != k.key {
+		return false
+	}
+	if k.key >= tcell.KeyCtrlA && k.key <= tcell.KeyCtrlZ {
+		return true
+	}
+	return event.Modifiers()&modifiers == k.mod&modifiers
+}
+
+// ParseKeySpec parses strings like "F1", "Ctrl+A", "Alt+p", "Space" or "n".
+func ParseKeySpec(spec string) (KeySpec, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return KeySpec{}, fmt.Errorf("%w: empty", errInvalidKeySpec)
+	}
+
+	parts := []string{spec}
+	if len(spec) > 1 {
+		parts = strings.Split(spec, "+")
+	}
+	keyName := parts[len(parts)-1]
+
+	var mod tcell.ModMask
+	for _, modifier := range parts[:len(parts)-1] {
+		switch strings.ToLower(modifier) {
+		case "ctrl":
+			mod |= tcell.ModCtrl
+		case "alt":
+			mod |= tcell.ModAlt
+		default:
+			return KeySpec{}, fmt.Errorf("%w: unknown modifier %q", errInvalidKeySpec, modifier)
+		}
+	}
+
+	if strings.EqualFold(keyName, "space") {
+		keyName = " "
+	}
+
+	if utf8.RuneCountInString(keyName) == 1 {
+		return parseRuneKey([]rune(keyName)[0], mod)
+	}
+
+	if key, ok := keysByName[strings.ToLower(keyName)]; ok {
+		return KeySpec{key: key, ch: 0, mod: mod, name: formatKeyName(mod, tcell.KeyNames[key])}, nil
+	}
+	return KeySpec{}, fmt.Errorf("%w: unknown key %q", errInvalidKeySpec, keyName)
+}
+
+// canonicalKeys are keys that win over other keys with the same name, e.g. Enter over Ctrl+M.
+var canonicalKeys = []tcell.Key{tcell.KeyEnter, tcell.KeyTab, tcell.KeyEscape, tcell.KeyBackspace}
+
+// keysByName maps lower case key names to keys. Named keys are taken in order of their codes,
+// so the same spec gives the same key whatever the order of tcell.KeyNames is.
+var keysByName = newKeysByName()
+
+func newKeysByName() map[string]tcell.Key {
+	keys := make([]tcell.Key, 0, len(tcell.KeyNames))
+	for key, name := range tcell.KeyNames {
+		if key != tcell.KeyRune && !strings.HasPrefix(name, "Ctrl-") {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	byName := make(map[string]tcell.Key, len(keys))
+	for _, key := range slices.Concat(canonicalKeys, keys) {
+		name := strings.ToLower(tcell.KeyNames[key])
+		if _, ok := byName[name]; !ok {
+			byName[name] = key
+		}
+	}
+	return byName
+}
+
+func parseRuneKey(ch rune, mod tcell.ModMask) (KeySpec, error) {
+	name := string(ch)
+	if ch == ' ' {
+		name = "Space"
+	}
+
+	if mod&tcell.ModCtrl == 0 {
+		return KeySpec{key: tcell.KeyRune, ch: ch, mod: mod, name: formatKeyName(mod, name)}, nil
+	}
+
+	lower := unicode.ToLower(ch)
+	if lower < 'a' || lower > 'z' {
+		return KeySpec{}, fmt.Errorf("%w: ctrl works only with letters", errInvalidKeySpec)
+	}
+	key := tcell.KeyCtrlA + tcell.Key(lower-'a')
+	return KeySpec{key: key, ch: 0, mod: mod, name: formatKeyName(mod, string(unicode.ToUpper(lower)))}, nil
+}
+
+func formatKeyName(mod tcell.ModMask, name string) string {
+	if mod&tcell.ModAlt != 0 {
+		name = "Alt+" + name
+	}
+	if mod&tcell.ModCtrl != 0 {
+		name = "Ctrl+" + name
+	}
+	return name
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeySpec(t *testing.T) {
+	tests := []struct {
+		spec  string
+		event *tcell.EventKey
+		name  string
+	}{
+		{"F1", tcell.NewEventKey(tcell.KeyF1, 0, tcell.ModNone), "F1"},
+		{"ctrl+a", tcell.NewEventKey(tcell.KeyCtrlA, 0, tcell.ModCtrl), "Ctrl+A"},
+		{"Space", tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), "Space"},
+		{"n", tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone), "n"},
+		{"Alt+p", tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModAlt), "Alt+p"},
+		{"Tab", tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone), "Tab"},
+		{"enter", tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), "Enter"},
+		{"ESC", tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), "Esc"},
+		{"+", tcell.NewEventKey(tcell.KeyRune, '+', tcell.ModNone), "+"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			spec, err := ParseKeySpec(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.name, spec.name)
+			assert.True(t, spec.Match(tt.event))
+		})
+	}
+}
+
+func TestKeysByName(t *testing.T) {
+	for _, key := range canonicalKeys {
+		assert.Equal(t, key, keysByName[strings.ToLower(tcell.KeyNames[key])])
+	}
+	for name, key := range keysByName {
+		assert.Equal(t, name, strings.ToLower(tcell.KeyNames[key]))
+	}
+}
+
+func TestParseKeySpec_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "Hyper+a", "Ctrl+1", "F99", "Hello"} {
+		_, err := ParseKeySpec(spec)
+		require.ErrorIs(t, err, errInvalidKeySpec, spec)
+	}
+}
+
+func TestKeySpec_MatchModifiers(t *testing.T) {
+	spec, err := ParseKeySpec("p")
+	require.NoError(t, err)
+
+	assert.False(t, spec.Match(tcell.NewEventKey(tcell.KeyRune, 'p', tcell.ModAlt)))
+	assert.False(t, spec.Match(tcell.NewEventKey(tcell.KeyRune, 'o', tcell.ModNone)))
+}
+
+func TestNewKeyBindings(t *testing.T) {
+	keys, err := NewKeyBindings(map[string]string{"tasks_page": "Ctrl+T"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Ctrl+T", keys.Name(actionTasksPage))
+	assert.Equal(t, "F1", keys.Name(actionFocusPage))
+	assert.True(t, keys.Match(actionTasksPage, tcell.NewEventKey(tcell.KeyCtrlT, 0, tcell.ModCtrl)))
+
+	_, err = NewKeyBindings(map[string]string{"unknown": "F1"})
+	require.ErrorIs(t, err, errUnknownAction)
+
+	// keys of widgets are only shown in help
+	_, err = NewKeyBindings(map[string]string{"quit": "q"})
+	require.ErrorIs(t, err, errUnknownAction)
+
+	// add_task and insert_record share Ctrl+A by default, but live on different pages
+	_, err = NewKeyBindings(map[string]string{"summary_page": "F1"})
+	require.ErrorIs(t, err, errKeyConflict)
+
+	_, err = NewKeyBindings(map[string]string{"delete_task": "Ctrl+A"})
+	require.ErrorIs(t, err, errKeyConflict)
+}
+
+func TestKeyBindings_Help(t *testing.T) {
+	keys, err := NewKeyBindings(nil)
+	require.NoError(t, err)
+
+	hints := keys.Help(allTasksPage)
+	assert.Contains(t, hints, keyHint{key: "Ctrl+A", description: "Create task"})
+	assert.NotContains(t, hints, keyHint{key: "Ctrl+Y", description: "Delete selected record"})
+}
+
+func TestKeyBindings_PageAndGlobalHelp(t *testing.T) {
+	keys, err := NewKeyBindings(map[string]string{"help": "h"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []keyHint{
+		{key: "Tab", description: "Focus page buttons"},
+		{key: "Enter", description: "Select records"},
+		{key: "↑/↓", description: "Move selection"},
+		{key: "←/→", description: "Select action"},
+		{key: "Esc", description: "Drop selection"},
+		{key: "Ctrl+A", description: "Create record"},
+		{key: "Ctrl+Y", description: "Delete selected record"},
+		{key: "Ctrl+E", description: "Export calendar (.ics)"},
+	}, keys.PageHelp(detailStatsPage))
+
+	global := keys.GlobalHelp()
+	assert.Contains(t, global, keyHint{key: "h", description: "Help"})
+	assert.Contains(t, global, keyHint{key: "F9", description: "Show/hide key tips"})
+	assert.Equal(t, keyHint{key: "Ctrl+C", description: "Quit"}, global[len(global)-1])
+	assert.NotContains(t, global, keyHint{key: "Ctrl+A", description: "Create task"})
+}
+
+func TestKeyBindings_Overrides(t *testing.T) {
+	keys, err := NewKeyBindings(map[string]string{"skip": "alt+s", "focus_page": "F1"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"skip": "Alt+s"}, keys.Overrides())
+}
@@ -15,13 +15,37 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// commands load config themselves, flags of the app aren't parsed for them
+	if len(os.Args) > 1 {
+		if command, ok := commands()[os.Args[1]]; ok {
+			if err = command(logger, os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	cfg, err := config.Init()
 	if err != nil {
 		logger.Error("can't initialization config", slog.Any("error", err))
 		os.Exit(1)
 	}
 
-	app := NewApplication(logger, cfg)
+	keys, err := NewKeyBindings(cfg.Keys)
+	if err != nil {
+		logger.Error("invalid keys in config", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	theme, err := NewTheme(cfg.Theme)
+	if err != nil {
+		logger.Error("invalid theme in config", slog.Any("error", err))
+		os.Exit(1)
+	}
+	theme.Apply()
+
+	app := NewApplication(logger, cfg, keys, theme)
 
 	// non blocking operation using goroutins
 	app.Run()
@@ -32,6 +56,14 @@ func main() {
 	}
 }
 
+func commands() map[string]func(*slog.Logger, []string) error {
+	return map[string]func(*slog.Logger, []string) error{
+		"export":      runExport,
+		"taskwarrior": runTaskwarrior,
+		"sync":        runSync,
+	}
+}
+
 func initLogger(logFilePath string) (*slog.Logger, error) {
 	file, err := os.OpenFile(logFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0666)
 	if err != nil {
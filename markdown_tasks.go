@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// markdownItem matches checklist item: list marker, check mark and text, e.g. "  - [x] Write report".
+	markdownItem = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(\]\s+)(.*)$`)
+	// markdownProgress matches progress marker of item, e.g. "(🍅 2/4)" or "(🍅 2/4 active)".
+	markdownProgress = regexp.MustCompile(`\(🍅\s*(\d+)\s*/\s*(\d+)(\s+active)?\)`)
+)
+
+// markdownFormat keeps tasks as checklist items of Markdown file, e.g. "- [ ] Write report (🍅 2/4)".
+// Progress and active task are kept in marker at the end of item, headings and other text are ignored.
+type markdownFormat struct{}
+
+func NewMarkdownTasks(path string) *FileTasks {
+	return NewFileTasks(path, markdownFormat{})
+}
+
+func (markdownFormat) parse(line string) (*Task, bool) {
+	item := markdownItem.FindStringSubmatch(line)
+	if item == nil {
+		return nil, false
+	}
+	text := item[4]
+
+	var completed, required int
+	active := false
+	if progress := markdownProgress.FindStringSubmatch(text); progress != nil {
+		completed, _ = strconv.Atoi(progress[1])
+		required, _ = strconv.Atoi(progress[2])
+		active = progress[3] != ""
+	}
+
+	return &Task{
+		ID:                 0,
+		Name:               markdownName(text),
+		PomodorosRequired:  required,
+		PomodorosCompleted: completed,
+		IsComplete:         item[2] != " ",
+		IsActive:           active,
+		CreateAt:           time.Time{},
+		UUID:               "",
+		CompleteAt:         time.Time{},
+		line:               "",
+	}, true
+}
+
+func (markdownFormat) update(line string, task *Task) string {
+	item := markdownItem.FindStringSubmatch(line)
+	if item == nil {
+		return line
+	}
+	prefix, mark, separator, text := item[1], item[2], item[3], item[4]
+
+	switch {
+	case !task.IsComplete:
+		mark = " "
+	case mark == " ":
+		mark = "x"
+	}
+
+	if task.Name != markdownName(text) {
+		text = task.Name
+		if progress := markdownProgress.FindString(item[4]); progress != "" {
+			text += " " + progress
+		}
+	}
+
+	progress := markdownProgressMarker(task)
+	switch {
+	case markdownProgress.MatchString(text):
+		text = markdownProgress.ReplaceAllLiteralString(text, progress)
+	case task.PomodorosRequired > 0 || task.PomodorosCompleted > 0 || task.IsActive:
+		text = strings.TrimRight(text, " ") + " " + progress
+	}
+
+	return prefix + mark + separator + text
+}
+
+func (f markdownFormat) format(task *Task) string {
+	return f.update("- [ ] "+task.Name, task)
+}
+
+// markdownName is item text without progress marker.
+func markdownName(text string) string {
+	return strings.Join(strings.Fields(markdownProgress.ReplaceAllString(text, "")), " ")
+}
+
+func markdownProgressMarker(task *Task) string {
+	marker := fmt.Sprintf("(🍅 %d/%d", task.PomodorosCompleted, task.PomodorosRequired)
+	if task.IsActive {
+		marker += " active"
+	}
+	return marker + ")"
+}
@@ -0,0 +1,80 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const markdownContent = `# Release
+
+Notes about - [ ] not a task.
+
+- [ ] Write   report (🍅 1/2 active)
+  * [X] Review PR
+1. [ ] Update changelog
+
+## Later
+- [ ] Refactor parser
+`
+
+func TestMarkdownTasks_Tasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TODO.md")
+	require.NoError(t, os.WriteFile(path, []byte(markdownContent), 0o600))
+	tasks := NewMarkdownTasks(path)
+
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+	require.Len(t, list, 4)
+
+	assert.Equal(t, 5, list[0].ID)
+	assert.Equal(t, "Write report", list[0].Name)
+	assert.Equal(t, 1, list[0].PomodorosCompleted)
+	assert.Equal(t, 2, list[0].PomodorosRequired)
+	assert.True(t, list[0].IsActive)
+	assert.False(t, list[0].IsComplete)
+
+	assert.Equal(t, "Review PR", list[1].Name)
+	assert.True(t, list[1].IsComplete)
+	assert.Equal(t, "Update changelog", list[2].Name)
+	assert.Equal(t, 10, list[3].ID)
+}
+
+func TestMarkdownTasks_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TODO.md")
+	require.NoError(t, os.WriteFile(path, []byte(markdownContent), 0o600))
+	tasks := NewMarkdownTasks(path)
+
+	require.NoError(t, tasks.IncPomodoroActiveTask())
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+
+	list[0].IsActive = false
+	require.NoError(t, tasks.UpdateTask(list[0]))
+	list[2].IsActive = true
+	require.NoError(t, tasks.UpdateTask(list[2]))
+	list[1].IsComplete = false
+	require.NoError(t, tasks.UpdateTask(list[1]))
+
+	require.NoError(t, tasks.CreateTask(&Task{Name: "Tag release", PomodorosRequired: 1}))
+
+	assert.Equal(t, `# Release
+
+Notes about - [ ] not a task.
+
+- [x] Write   report (🍅 2/2)
+  * [ ] Review PR
+1. [ ] Update changelog (🍅 0/0 active)
+
+## Later
+- [ ] Refactor parser
+- [ ] Tag release (🍅 0/1)
+`, readTestFile(t, path))
+
+	require.NoError(t, tasks.DeleteTask(&Task{ID: 11}))
+	require.ErrorIs(t, tasks.DeleteTask(&Task{ID: 1}), errTaskNotFound)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsNamespace = "pomotrack"
+
+// Metrics keeps prometheus collectors updated from timer state events.
+type Metrics struct {
+	logger       *slog.Logger
+	stateManager *StateManager
+	taskTracker  taskManager
+	events       chan StateEvent
+	registry     *prometheus.Registry
+	server       *http.Server
+
+	timerState    *prometheus.GaugeVec
+	interruptions prometheus.Counter
+	completed     prometheus.Counter
+
+	mu               sync.Mutex
+	focusSeconds     float64
+	focusStartedAt   time.Time
+	completedToday   int
+	completedTodayAt time.Time
+}
+
+func NewMetrics(l *slog.Logger, addr string, sm *StateManager, tt taskManager, pt pomodoroTracker) *Metrics {
+	m := &Metrics{
+		logger:       l,
+		stateManager: sm,
+		taskTracker:  tt,
+		events:       make(chan StateEvent),
+		registry:     prometheus.NewRegistry(),
+		server:       nil,
+		timerState: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "timer_state",
+			Help:      "Current timer state, 1 for the current timer and state pair.",
+		}, []string{"timer", "state"}),
+		interruptions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "interruptions_total",
+			Help:      "Number of paused focus sessions.",
+		}),
+		completed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "pomodoros_completed_total",
+			Help:      "Number of finished focus sessions since start.",
+		}),
+		mu:               sync.Mutex{},
+		focusSeconds:     0,
+		focusStartedAt:   time.Time{},
+		completedToday:   0,
+		completedTodayAt: time.Now(),
+	}
+
+	if today, err := pt.TodayPomodoros(); err == nil {
+		for _, pomodoro := range today {
+			// like completed counter, skipped and abandoned sessions aren't counted
+			if pomodoro.counted() && pomodoro.Outcome == OutcomeCompleted {
+				m.completedToday++
+			}
+		}
+	} else {
+		l.Error("metrics: can't get today pomodoros", slog.Any("error", err))
+	}
+
+	m.registerCollectors()
+	m.setTimerState(FocusTimer, StatePaused)
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	m.server = &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: apiReadHeaderTimeout,
+	}
+	return m
+}
+
+func (m *Metrics) registerCollectors() {
+	m.registry.MustRegister(
+		m.timerState,
+		m.interruptions,
+		m.completed,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "timer_remaining_seconds",
+			Help:      "Seconds left on the current timer.",
+		}, func() float64 {
+			return m.stateManager.timeToFinish(m.stateManager.CurrentTimer()).Seconds()
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "pomodoros_today",
+			Help:      "Number of focus sessions today.",
+		}, m.pomodorosToday),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "focus_seconds_total",
+			Help:      "Seconds spent in focus since start, including running session.",
+		}, m.focusSecondsTotal),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "active_task_pomodoros_completed",
+			Help:      "Completed pomodoros of the active task.",
+		}, func() float64 {
+			task := m.activeTask()
+			if task == nil {
+				return 0
+			}
+			return float64(task.PomodorosCompleted)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: metricsNamespace,
+			Name:      "active_task_pomodoros_required",
+			Help:      "Required pomodoros of the active task.",
+		}, func() float64 {
+			task := m.activeTask()
+			if task == nil {
+				return 0
+			}
+			return float64(task.PomodorosRequired)
+		}),
+	)
+}
+
+// Run starts metrics http server, it's blocking operation.
+func (m *Metrics) Run() error {
+	m.logger.Info("starting metrics server", slog.String("address", m.server.Addr))
+	if err := m.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server stopped: %w", err)
+	}
+	return nil
+}
+
+// Events returns channel HandleStateChanges reads. Every event has to be sent to it,
+// so counters don't miss transitions; it's closed to stop HandleStateChanges.
+func (m *Metrics) Events() chan<- StateEvent {
+	return m.events
+}
+
+// HandleStateChanges consumes the same events as PomodoroManager.
+func (m *Metrics) HandleStateChanges() {
+	for event := range m.events {
+		m.setTimerState(event.TimerType, event.NewState)
+
+		if !event.TimerType.IsFocus() {
+			continue
+		}
+
+		m.mu.Lock()
+		switch event.NewState {
+		case StateActive:
+			m.focusStartedAt = time.Now()
+		case StatePaused:
+			m.stopFocusSegment()
+			m.interruptions.Inc()
+		case StateFinished:
+			m.stopFocusSegment()
+			if !event.Skipped && !event.Abandoned && !event.TooShort {
+				m.completed.Inc()
+				m.resetTodayIfNeeded()
+				m.completedToday++
+			}
+		}
+		m.mu.Unlock()
+	}
+}
+
+func (m *Metrics) setTimerState(timerType TimerType, state TimerState) {
+	for _, t := range []TimerType{FocusTimer, BreakTimer, StopwatchTimer} {
+		for _, s := range []TimerState{StatePaused, StateActive, StateFinished, StateOvertime} {
+			var value float64
+			if t == timerType && s == state {
+				value = 1
+			}
+			m.timerState.WithLabelValues(t.String(), s.String()).Set(value)
+		}
+	}
+}
+
+// stopFocusSegment must be called with mu locked.
+func (m *Metrics) stopFocusSegment() {
+	if m.focusStartedAt.IsZero() {
+		return
+	}
+	m.focusSeconds += time.Since(m.focusStartedAt).Seconds()
+	m.focusStartedAt = time.Time{}
+}
+
+// resetTodayIfNeeded must be called with mu locked.
+func (m *Metrics) resetTodayIfNeeded() {
+	now := time.Now()
+	if now.YearDay() != m.completedTodayAt.YearDay() || now.Year() != m.completedTodayAt.Year() {
+		m.completedToday = 0
+	}
+	m.completedTodayAt = now
+}
+
+func (m *Metrics) pomodorosToday() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resetTodayIfNeeded()
+	return float64(m.completedToday)
+}
+
+func (m *Metrics) focusSecondsTotal() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	total := m.focusSeconds
+	if !m.focusStartedAt.IsZero() {
+		total += time.Since(m.focusStartedAt).Seconds()
+	}
+	return total
+}
+
+func (m *Metrics) activeTask() *Task {
+	tasks, err := m.taskTracker.Tasks()
+	if err != nil {
+		m.logger.Error("metrics: can't get tasks", slog.Any("error", err))
+		return nil
+	}
+	for _, task := range tasks {
+		if task.IsActive {
+			return task
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_HandleStateChanges(t *testing.T) {
+	for _, outcome := range []SessionOutcome{OutcomeCompleted, OutcomeSkipped, OutcomeVoided, OutcomeIncomplete} {
+		require.NoError(t, s.CreatePomodoro(&Pomodoro{StartAt: time.Now(), FinishAt: time.Now(), Outcome: outcome}))
+	}
+	defer clearTable()
+
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, make(chan StateEvent),
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration}, s)
+	metrics := NewMetrics(slog.Default(), "127.0.0.1:0", stateManager, s, NewPomodoroManager(slog.Default(), s, nil))
+	assert.InDelta(t, 1, metrics.pomodorosToday(), 0)
+
+	done := make(chan struct{})
+	go func() {
+		metrics.HandleStateChanges()
+		close(done)
+	}()
+
+	events := metrics.Events()
+	events <- StateEvent{TimerType: FocusTimer, NewState: StateActive}
+	events <- StateEvent{TimerType: FocusTimer, NewState: StatePaused}
+	events <- StateEvent{TimerType: FocusTimer, NewState: StateActive}
+	events <- StateEvent{TimerType: FocusTimer, NewState: StateFinished}
+	events <- StateEvent{TimerType: FocusTimer, NewState: StateActive}
+	events <- StateEvent{TimerType: FocusTimer, NewState: StateFinished, TooShort: true}
+	events <- StateEvent{TimerType: BreakTimer, NewState: StateActive}
+	close(events)
+	<-done
+
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.interruptions), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.completed), 0)
+	assert.InDelta(t, 2, metrics.pomodorosToday(), 0)
+	assert.Positive(t, metrics.focusSecondsTotal())
+	assert.InDelta(t, 1, testutil.ToFloat64(metrics.timerState.WithLabelValues("break", "active")), 0)
+	assert.InDelta(t, 0, testutil.ToFloat64(metrics.timerState.WithLabelValues("focus", "finished")), 0)
+
+	rec := httptest.NewRecorder()
+	metrics.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "pomotrack_timer_remaining_seconds 10")
+}
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+ALTER TABLE pomodoros ADD COLUMN kind TEXT NOT NULL DEFAULT 'focus';
+ALTER TABLE pomodoros ADD COLUMN outcome TEXT NOT NULL DEFAULT 'completed';
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+ALTER TABLE pomodoros DROP COLUMN outcome;
+ALTER TABLE pomodoros DROP COLUMN kind;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+CREATE TABLE IF NOT EXISTS pomodoro_segments (
+    id INTEGER PRIMARY KEY,
+    pomodoro_id INTEGER NOT NULL REFERENCES pomodoros (id) ON DELETE CASCADE,
+    start_at TIMESTAMP NOT NULL,
+    finish_at TIMESTAMP NOT NULL,
+    duration INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS pomodoro_segments_pomodoro_id ON pomodoro_segments (pomodoro_id);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+DROP TABLE IF EXISTS pomodoro_segments;
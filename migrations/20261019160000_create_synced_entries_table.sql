@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+CREATE TABLE IF NOT EXISTS synced_entries (
+    pomodoro_id INTEGER NOT NULL,
+    service TEXT NOT NULL,
+    remote_id TEXT NOT NULL DEFAULT '',
+    synced_at TIMESTAMP NOT NULL DEFAULT current_timestamp,
+    PRIMARY KEY (pomodoro_id, service)
+);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+DROP TABLE IF EXISTS synced_entries;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+ALTER TABLE tasks ADD COLUMN uuid TEXT;
+CREATE UNIQUE INDEX IF NOT EXISTS tasks_uuid ON tasks (uuid);
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+DROP INDEX IF EXISTS tasks_uuid;
+ALTER TABLE tasks DROP COLUMN uuid;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- +goose StatementEnd
+ALTER TABLE pomodoros ADD COLUMN git_repo TEXT NOT NULL DEFAULT '';
+ALTER TABLE pomodoros ADD COLUMN git_branch TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+-- +goose StatementEnd
+ALTER TABLE pomodoros DROP COLUMN git_branch;
+ALTER TABLE pomodoros DROP COLUMN git_repo;
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const orgTimeFormat = "2006-01-02 Mon 15:04"
+
+// orgHeading is task heading with clock entries of its sessions. Sessions started in git
+// repository are clocked in child heading of repository and branch.
+type orgHeading struct {
+	title    string
+	clocks   []orgClock
+	children []*orgHeading
+}
+
+type orgClock struct {
+	start time.Time
+	end   time.Time
+}
+
+// writeOrg writes focus sessions as Org-mode CLOCK entries in :LOGBOOK: drawer of heading per task.
+// Session is clocked by its segments, so pauses aren't counted; sessions stored before segments
+// are clocked from start to finish. Like Org itself, the newest entry goes first, times are local.
+// Child headings keep their own entries, Org sums them into task in clock reports.
+func writeOrg(w io.Writer, sessions []*Pomodoro, segments map[int][]*Segment) error {
+	var headings []*orgHeading
+	byTitle := make(map[string]*orgHeading)
+
+	for _, session := range sessions {
+		if session.Kind == SessionBreak || !session.counted() {
+			continue
+		}
+		title := orgHeadingTitle(sessionSummary(session))
+		heading, ok := byTitle[title]
+		if !ok {
+			heading = &orgHeading{title: title, clocks: nil, children: nil}
+			byTitle[title] = heading
+			headings = append(headings, heading)
+		}
+		if session.GitRepo != "" {
+			heading = heading.child(orgHeadingTitle(gitLabel(session)))
+		}
+		heading.clocks = append(heading.clocks, sessionClocks(session, segments[session.ID])...)
+	}
+
+	var b strings.Builder
+	b.WriteString("#+TITLE: PomoTrack\n")
+	for _, heading := range headings {
+		b.WriteString("\n")
+		heading.write(&b, 1)
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("can't write org file: %w", err)
+	}
+	return nil
+}
+
+func (h *orgHeading) child(title string) *orgHeading {
+	for _, child := range h.children {
+		if child.title == title {
+			return child
+		}
+	}
+	child := &orgHeading{title: title, clocks: nil, children: nil}
+	h.children = append(h.children, child)
+	return child
+}
+
+func (h *orgHeading) write(b *strings.Builder, level int) {
+	b.WriteString(strings.Repeat("*", level) + " " + h.title + "\n")
+	if len(h.clocks) > 0 {
+		b.WriteString(":LOGBOOK:\n")
+		for i := len(h.clocks) - 1; i >= 0; i-- {
+			b.WriteString(h.clocks[i].String() + "\n")
+		}
+		b.WriteString(":END:\n")
+	}
+	for _, child := range h.children {
+		child.write(b, level+1)
+	}
+}
+
+func sessionClocks(session *Pomodoro, segments []*Segment) []orgClock {
+	if len(segments) == 0 {
+		return []orgClock{{start: session.StartAt, end: session.FinishAt}}
+	}
+
+	clocks := make([]orgClock, 0, len(segments))
+	for _, segment := range segments {
+		clocks = append(clocks, orgClock{start: segment.StartAt, end: segment.FinishAt})
+	}
+	return clocks
+}
+
+// String formats entry the way Org does: "CLOCK: [2024-10-07 Mon 10:00]--[2024-10-07 Mon 10:25] =>  0:25".
+// Org keeps minutes only, so duration is counted between printed times.
+func (c orgClock) String() string {
+	start, end := c.start.Local().Truncate(time.Minute), c.end.Local().Truncate(time.Minute)
+	minutes := int(end.Sub(start).Minutes())
+	return fmt.Sprintf("CLOCK: [%s]--[%s] => %2d:%02d",
+		start.Format(orgTimeFormat), end.Format(orgTimeFormat), minutes/60, minutes%60)
+}
+
+// orgHeadingTitle keeps heading on one line and stops it from being read as a deeper heading.
+func orgHeadingTitle(title string) string {
+	title = strings.TrimLeft(strings.Join(strings.Fields(title), " "), "* ")
+	if title == "" {
+		return "Focus"
+	}
+	return title
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOrg(t *testing.T) {
+	start := time.Date(2024, time.October, 7, 10, 0, 30, 0, time.Local)
+	sessions := []*Pomodoro{
+		{ID: 1, StartAt: start, FinishAt: start.Add(25 * time.Minute), SecondsDuration: 25 * 60,
+			Kind: SessionFocus, Outcome: OutcomeCompleted, Task: "Write report"},
+		{ID: 2, StartAt: start.Add(25 * time.Minute), FinishAt: start.Add(30 * time.Minute), SecondsDuration: 5 * 60,
+			Kind: SessionBreak, Outcome: OutcomeCompleted},
+		{ID: 3, StartAt: start.Add(time.Hour), FinishAt: start.Add(2*time.Hour + 35*time.Minute),
+			SecondsDuration: 90 * 60, Kind: SessionFocus, Outcome: OutcomeCompleted},
+		{ID: 4, StartAt: start.Add(26 * time.Hour), FinishAt: start.Add(26*time.Hour + 50*time.Minute),
+			SecondsDuration: 45 * 60, Kind: SessionFocus, Outcome: OutcomeSkipped, Task: "Write report"},
+		{ID: 5, StartAt: start.Add(48 * time.Hour), FinishAt: start.Add(48*time.Hour + 25*time.Minute),
+			SecondsDuration: 25 * 60, Kind: SessionFocus, Outcome: OutcomeCompleted, Task: "Write report",
+			GitRepo: "/home/user/pomotrack", GitBranch: "feature/org"},
+		{ID: 6, StartAt: start.Add(50 * time.Hour), FinishAt: start.Add(50*time.Hour + 25*time.Minute),
+			SecondsDuration: 25 * 60, Kind: SessionFocus, Outcome: OutcomeCompleted,
+			GitRepo: "/home/user/pomotrack", GitBranch: "main"},
+		{ID: 7, StartAt: start.Add(51 * time.Hour), FinishAt: start.Add(51*time.Hour + 5*time.Minute),
+			SecondsDuration: 5 * 60, Kind: SessionFocus, Outcome: OutcomeVoided, Task: "Write report"},
+		{ID: 8, StartAt: start.Add(52 * time.Hour), FinishAt: start.Add(52*time.Hour + 2*time.Minute),
+			SecondsDuration: 2 * 60, Kind: SessionFocus, Outcome: OutcomeIncomplete, Task: "Read"},
+	}
+	segments := map[int][]*Segment{
+		4: {
+			{ID: 1, PomodoroID: 4, StartAt: start.Add(26 * time.Hour), FinishAt: start.Add(26*time.Hour + 20*time.Minute),
+				SecondsDuration: 20 * 60},
+			{ID: 2, PomodoroID: 4, StartAt: start.Add(26*time.Hour + 25*time.Minute),
+				FinishAt: start.Add(26*time.Hour + 50*time.Minute), SecondsDuration: 25 * 60},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeOrg(&buf, sessions, segments))
+
+	assert.Equal(t, `#+TITLE: PomoTrack
+
+* Write report
+:LOGBOOK:
+CLOCK: [2024-10-08 Tue 12:25]--[2024-10-08 Tue 12:50] =>  0:25
+CLOCK: [2024-10-08 Tue 12:00]--[2024-10-08 Tue 12:20] =>  0:20
+CLOCK: [2024-10-07 Mon 10:00]--[2024-10-07 Mon 10:25] =>  0:25
+:END:
+** pomotrack/feature/org
+:LOGBOOK:
+CLOCK: [2024-10-09 Wed 10:00]--[2024-10-09 Wed 10:25] =>  0:25
+:END:
+
+* Focus
+:LOGBOOK:
+CLOCK: [2024-10-07 Mon 11:00]--[2024-10-07 Mon 12:35] =>  1:35
+:END:
+** pomotrack/main
+:LOGBOOK:
+CLOCK: [2024-10-09 Wed 12:00]--[2024-10-09 Wed 12:25] =>  0:25
+:END:
+`, buf.String())
+}
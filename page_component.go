@@ -5,27 +5,47 @@ import (
 )
 
 type Page struct {
-	name   PageName
-	resize bool
-	render func() tview.Primitive
+	name      PageName
+	resize    bool
+	fullWidth bool
+	render    func() tview.Primitive
 }
 
 func NewPageComponent(name PageName, resize bool, render func() tview.Primitive) *Page {
 	return &Page{
-		name:   name,
-		resize: resize,
-		render: render,
+		name:      name,
+		resize:    resize,
+		fullWidth: false,
+		render:    render,
 	}
 }
 
-func (p *Page) WithBottomPanel() tview.Primitive {
-	hotKeysPanel := constructBottomPanel(p.name)
+// SetFullWidth makes page content use whole screen width instead of the centered column.
+func (p *Page) SetFullWidth() *Page {
+	p.fullWidth = true
+	return p
+}
+
+// WithBottomPanel lays out page with hot keys panel under it and optional key tips sidebar on the left.
+func (p *Page) WithBottomPanel(hotKeysPanel tview.Primitive, keyTips tview.Primitive) tview.Primitive {
 	grid := tview.NewGrid().
 		SetRows(0, 1).
 		SetColumns(0, 23, 23, 0).
 		SetBorders(true)
-	grid.AddItem(p.render(), 0, 1, 1, 2, 0, 0, true)
-	grid.AddItem(hotKeysPanel, 1, 1, 1, 2, 0, 0, false)
+
+	offset := 0
+	if keyTips != nil {
+		offset = 1
+		grid.SetColumns(keyTipsWidth, 0, 23, 23, 0)
+		grid.AddItem(keyTips, 0, 0, 2, 1, 0, 0, false)
+	}
+
+	if p.fullWidth {
+		grid.AddItem(p.render(), 0, offset, 1, 4, 0, 0, true)
+	} else {
+		grid.AddItem(p.render(), 0, offset+1, 1, 2, 0, 0, true)
+	}
+	grid.AddItem(hotKeysPanel, 1, offset, 1, 4, 0, 0, false)
 
 	return grid
 }
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const flowtimeTitle = "Flowtime"
+
+// renderFlowtimePausePage shows stopwatch on pause: counted time and break it earns.
+func (m *UIManager) renderFlowtimePausePage() func() tview.Primitive {
+	return func() tview.Primitive {
+		elapsed := m.stateManager.elapsed(StopwatchTimer)
+
+		titleText := tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter).
+			SetText(flowtimeTitle)
+
+		elapsedText := tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter).
+			SetText(formatDuration(elapsed))
+
+		startButton := tview.NewButton("▶ Start").SetSelectedFunc(func() {
+			m.changeState(StateActive, StopwatchTimer)
+		})
+
+		breakText := tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter).
+			SetText(m.theme.Muted.Wrap(m.flowtimeBreakText(elapsed)))
+
+		grid := tview.NewGrid().
+			SetRows(0, 1, 3, 1, 1, 0).
+			SetColumns(0, 0, 15, 0, 0).
+			SetBorders(true)
+
+		grid.AddItem(titleText, 1, 2, 1, 1, 0, 0, false)
+		grid.AddItem(elapsedText, 2, 2, 1, 1, 0, 0, false)
+		grid.AddItem(startButton, 3, 2, 1, 1, 0, 0, true)
+		grid.AddItem(breakText, 4, 1, 1, 3, 0, 0, false)
+
+		grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if m.keys.Match(actionStartPause, event) {
+				m.changeState(StateActive, StopwatchTimer)
+				return nil
+			}
+			return event
+		})
+
+		return grid
+	}
+}
+
+// renderFlowtimeActivePage shows running stopwatch with big digits.
+func (m *UIManager) renderFlowtimeActivePage() func() tview.Primitive {
+	return func() tview.Primitive {
+		stopwatch := tview.NewBox().
+			SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+				elapsed := m.stateManager.elapsed(StopwatchTimer)
+				drawClock(screen, x, y, width, height, m.theme.Focus, "Time to flow", formatDuration(elapsed),
+					m.theme.Muted.Wrap(m.flowtimeBreakText(elapsed)))
+				return x, y, width, height
+			})
+
+		pauseButton := tview.NewButton("Pause").SetSelectedFunc(func() {
+			m.changeState(StatePaused, StopwatchTimer)
+		})
+		// stopwatch can't be paused in strict mode, only abandoned
+		if m.stateManager.Strict() {
+			pauseButton.SetLabel("Abandon").SetSelectedFunc(func() {
+				m.changeState(StateFinished, StopwatchTimer)
+			})
+		}
+
+		finishButton := tview.NewButton("→").SetSelectedFunc(func() {
+			m.changeState(StateFinished, StopwatchTimer)
+		})
+
+		grid := tview.NewGrid().
+			SetRows(0, 1, 1).
+			SetColumns(0, 10, 5, 0).
+			SetBorders(true)
+
+		grid.AddItem(stopwatch, 0, 0, 1, 4, 0, 0, false)
+		grid.AddItem(pauseButton, 1, 1, 1, 1, 0, 0, true)
+		grid.AddItem(finishButton, 1, 2, 1, 1, 0, 0, false)
+
+		grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch {
+			case m.keys.Match(actionStartPause, event):
+				m.changeState(StatePaused, StopwatchTimer)
+				return nil
+			case m.keys.Match(actionSkip, event):
+				m.changeState(StateFinished, StopwatchTimer)
+				return nil
+			}
+
+			switch event.Key() {
+			case tcell.KeyTAB, tcell.KeyLeft, tcell.KeyRight:
+				if m.ui.GetFocus() == pauseButton {
+					m.ui.SetFocus(finishButton)
+				} else {
+					m.ui.SetFocus(pauseButton)
+				}
+			default:
+				return event
+			}
+			return nil
+		})
+
+		return grid
+	}
+}
+
+// flowtimeBreakText returns line like "break after it: 10m".
+func (m *UIManager) flowtimeBreakText(elapsed time.Duration) string {
+	return fmt.Sprintf("break after it: %s", formatConfigDuration(m.stateManager.BreakAfterFlow(elapsed)))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/rivo/tview"
+)
+
+const (
+	plansPage  = "Plans"
+	plansWidth = 40
+)
+
+// showPlans opens list of plans from config over pause page, selected plan starts at once.
+func (m *UIManager) showPlans() {
+	if len(m.config.Plans) == 0 {
+		m.showNotice(m.theme.Muted.Wrap("No plans in config"))
+		return
+	}
+
+	list := tview.NewList().ShowSecondaryText(false)
+	shortCut := '1'
+	for _, plan := range m.config.Plans {
+		list.AddItem(tview.Escape(plan.Name), "", shortCut, func() {
+			m.closeOverlay(plansPage)
+			// paused session is finished before plan starts, each state change waits for UI to draw it
+			go func() {
+				if err := m.plans.Start(plan); err != nil {
+					m.logger.Error("can't start plan", slog.String("plan", plan.Name), slog.Any("error", err))
+					m.ui.QueueUpdateDraw(func() {
+						m.showNotice(m.theme.Danger.Wrap(tview.Escape(err.Error())))
+					})
+				}
+			}()
+		})
+		// plans after the ninth have no shortcut
+		if shortCut != 0 && shortCut < '9' {
+			shortCut++
+		} else {
+			shortCut = 0
+		}
+	}
+	list.SetBorder(true).SetTitle(" Session plans (Esc to close) ")
+	list.SetDoneFunc(func() {
+		m.closeOverlay(plansPage)
+	})
+
+	overlay := tview.NewGrid().
+		SetRows(0, len(m.config.Plans)+2, 0).
+		SetColumns(0, plansWidth, 0).
+		AddItem(list, 1, 1, 1, 1, 0, 0, true)
+
+	m.pages.AddPage(plansPage, overlay, true, true)
+	m.ui.SetFocus(list)
+}
+
+func (m *UIManager) abortPlan() {
+	status, ok := m.plans.Status()
+	if !ok {
+		return
+	}
+	m.plans.Abort()
+	if m.currentPage != nil {
+		m.AddPageAndSwitch(m.currentPage)
+	}
+	m.showNotice(m.theme.Highlight.Wrap(tview.Escape("Plan aborted: " + status.name)))
+}
+
+// withPlanStatus adds running plan step to title of active page.
+func (m *UIManager) withPlanStatus(title string) string {
+	status, ok := m.plans.Status()
+	if !ok {
+		return title
+	}
+	return fmt.Sprintf("%s · %s", title, tview.Escape(planStatusText(status)))
+}
+
+// planStatusText returns line like "exam prep 2/8, next: break 10m".
+func planStatusText(status planStatus) string {
+	next := "last step"
+	if status.next != nil {
+		next = "next: " + status.next.String()
+	}
+	return fmt.Sprintf("%s %d/%d, %s", status.name, status.step, status.steps, next)
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/rivo/tview"
+)
+
+func (m *UIManager) NewSettingsPage() *Page {
+	return NewPageComponent(settingsPage, true, m.renderSettingsGrid(""))
+}
+
+// settingsForm keeps form fields to collect new config on save.
+type settingsForm struct {
+	form *tview.Form
+
+	profile *tview.DropDown
+	// timers of default profile, named profiles and empty one that adds profile
+	timers      []timerFields
+	showKeyTips *tview.Checkbox
+	themeName   *tview.DropDown
+	colors      []*tview.InputField
+	// plans of config and empty one that adds plan
+	plans             []planFields
+	flowDivisor       *tview.InputField
+	flowMinBreak      *tview.InputField
+	flowMaxBreak      *tview.InputField
+	flowRules         *tview.InputField
+	pauseMaxCount     *tview.InputField
+	pauseMaxTime      *tview.InputField
+	pauseVoid         *tview.Checkbox
+	minDuration       *tview.InputField
+	minPercent        *tview.InputField
+	minDiscard        *tview.Checkbox
+	syncService       *tview.DropDown
+	syncBaseURL       *tview.InputField
+	syncToken         *tview.InputField
+	syncWorkspace     *tview.InputField
+	syncProject       *tview.InputField
+	syncProjects      *tview.InputField
+	syncAfterPomodoro *tview.Checkbox
+	taskSource        *tview.DropDown
+	taskFile          *tview.InputField
+	apiEnabled        *tview.Checkbox
+	apiAddress        *tview.InputField
+	metricsEnabled    *tview.Checkbox
+	metricsAddress    *tview.InputField
+	keys              map[Action]*tview.InputField
+}
+
+// timerFields edit timer settings of one profile, default profile has no name field.
+type timerFields struct {
+	name            *tview.InputField
+	focusDuration   *tview.InputField
+	breakDuration   *tview.InputField
+	hiddenFocusTime *tview.Checkbox
+	flowMode        *tview.Checkbox
+	strict          *tview.Checkbox
+}
+
+// planFields edit one plan, steps are written in one line (see formatPlanSteps).
+type planFields struct {
+	name  *tview.InputField
+	steps *tview.InputField
+}
+
+// Options of drop downs for empty sync service and tasks source.
+const (
+	syncOff        = "off"
+	taskSourceBase = "database"
+)
+
+func (m *UIManager) renderSettingsGrid(message string) func() tview.Primitive {
+	return func() tview.Primitive {
+		status := tview.NewTextView().
+			SetDynamicColors(true).
+			SetTextAlign(tview.AlignCenter).
+			SetText(message)
+
+		settings := m.newSettingsForm()
+		settings.form.AddButton("Save", func() {
+			if err := m.saveSettings(settings); err != nil {
+				status.SetText(m.theme.Danger.Wrap(tview.Escape(err.Error())))
+			}
+		})
+		settings.form.AddButton("Reset", func() {
+			m.AddPageAndSwitch(m.NewSettingsPage())
+		})
+
+		grid := tview.NewGrid().
+			SetRows(0, 1).
+			SetColumns(0).
+			SetBorders(true)
+
+		grid.AddItem(settings.form, 0, 0, 1, 1, 0, 0, true)
+		grid.AddItem(status, 1, 0, 1, 1, 0, 0, false)
+		return grid
+	}
+}
+
+func (m *UIManager) newSettingsForm() *settingsForm {
+	cfg := m.config
+	themeName := cfg.Theme.Name
+	if themeName == "" {
+		themeName = defaultThemeName
+	}
+	themes := themeNames()
+	profiles := cfg.ProfileNames()
+	syncService := cfg.Sync.Service
+	if syncService == "" {
+		syncService = syncOff
+	}
+	taskSource := cfg.Tasks.Source
+	if taskSource == "" {
+		taskSource = taskSourceBase
+	}
+
+	settings := &settingsForm{
+		form:        tview.NewForm().SetItemPadding(0),
+		profile:     newDropDown("Profile", profiles, cfg.ActiveProfile()),
+		timers:      nil,
+		showKeyTips: tview.NewCheckbox().SetLabel("Show key tips").SetChecked(cfg.UI.ShowKeyTips),
+		themeName:   newDropDown("Theme", themes, themeName),
+		colors:      nil,
+		plans:       nil,
+		flowDivisor: newInputField("Flowtime divisor", formatOptionalInt(cfg.Flowtime.Divisor)),
+		flowMinBreak: newInputField("Flowtime min break",
+			formatOptionalDuration(cfg.Flowtime.MinBreak)),
+		flowMaxBreak: newInputField("Flowtime max break",
+			formatOptionalDuration(cfg.Flowtime.MaxBreak)),
+		flowRules:         newInputField("Flowtime rules", formatFlowtimeRules(cfg.Flowtime.Rules)),
+		pauseMaxCount:     newInputField("Max pauses", formatOptionalInt(cfg.Pauses.MaxCount)),
+		pauseMaxTime:      newInputField("Max pause time", formatOptionalDuration(cfg.Pauses.MaxTime)),
+		pauseVoid:         tview.NewCheckbox().SetLabel("Void paused too much").SetChecked(cfg.Pauses.Void),
+		minDuration:       newInputField("Minimum session", formatOptionalDuration(cfg.Minimum.Duration)),
+		minPercent:        newInputField("Minimum percent", formatOptionalInt(cfg.Minimum.Percent)),
+		minDiscard:        tview.NewCheckbox().SetLabel("Discard short").SetChecked(cfg.Minimum.Discard),
+		syncService:       newDropDown("Sync service", []string{syncOff, config.SyncToggl, config.SyncClockify}, syncService),
+		syncBaseURL:       newInputField("Sync base URL", cfg.Sync.BaseURL),
+		syncToken:         newInputField("Sync token", cfg.Sync.Token).SetMaskCharacter('*'),
+		syncWorkspace:     newInputField("Sync workspace", cfg.Sync.Workspace),
+		syncProject:       newInputField("Sync project", cfg.Sync.Project),
+		syncProjects:      newInputField("Sync task projects", formatProjects(cfg.Sync.Projects)),
+		syncAfterPomodoro: tview.NewCheckbox().SetLabel("Sync after pomodoro").SetChecked(cfg.Sync.AfterPomodoro),
+		taskSource: newDropDown("Tasks source",
+			[]string{taskSourceBase, config.TaskSourceTodoTxt, config.TaskSourceMarkdown}, taskSource),
+		taskFile:       newInputField("Tasks file", cfg.Tasks.File),
+		apiEnabled:     tview.NewCheckbox().SetLabel("API enabled").SetChecked(cfg.API.Enabled),
+		apiAddress:     newInputField("API address", cfg.API.Address),
+		metricsEnabled: tview.NewCheckbox().SetLabel("Metrics enabled").SetChecked(cfg.Metrics.Enabled),
+		metricsAddress: newInputField("Metrics address", cfg.Metrics.Address),
+		keys:           make(map[Action]*tview.InputField),
+	}
+
+	// cleared name removes profile or plan, filled name of the last empty one adds it
+	settings.form.AddFormItem(settings.profile)
+	settings.addTimerFields("", nil, cfg.Timer)
+	for i, profile := range cfg.Profiles {
+		settings.addTimerFields(fmt.Sprintf("Profile %d", i+1), &profile.Name, profile.Timer)
+	}
+	newProfile := ""
+	settings.addTimerFields("New profile", &newProfile, cfg.Timer)
+
+	for i, plan := range cfg.Plans {
+		settings.addPlanFields(fmt.Sprintf("Plan %d", i+1), plan)
+	}
+	settings.addPlanFields("New plan", config.Plan{Name: "", Steps: nil})
+
+	settings.form.
+		AddFormItem(settings.flowDivisor).
+		AddFormItem(settings.flowMinBreak).
+		AddFormItem(settings.flowMaxBreak).
+		AddFormItem(settings.flowRules).
+		AddFormItem(settings.pauseMaxCount).
+		AddFormItem(settings.pauseMaxTime).
+		AddFormItem(settings.pauseVoid).
+		AddFormItem(settings.minDuration).
+		AddFormItem(settings.minPercent).
+		AddFormItem(settings.minDiscard).
+		AddFormItem(settings.showKeyTips).
+		AddFormItem(settings.themeName)
+
+	theme := cfg.Theme
+	for _, color := range themeColorFields(&theme) {
+		field := tview.NewInputField().SetLabel(color.label + " color").SetText(*color.value)
+		settings.form.AddFormItem(field)
+		settings.colors = append(settings.colors, field)
+	}
+
+	settings.form.
+		AddFormItem(settings.syncService).
+		AddFormItem(settings.syncBaseURL).
+		AddFormItem(settings.syncToken).
+		AddFormItem(settings.syncWorkspace).
+		AddFormItem(settings.syncProject).
+		AddFormItem(settings.syncProjects).
+		AddFormItem(settings.syncAfterPomodoro).
+		AddFormItem(settings.taskSource).
+		AddFormItem(settings.taskFile).
+		AddFormItem(settings.apiEnabled).
+		AddFormItem(settings.apiAddress).
+		AddFormItem(settings.metricsEnabled).
+		AddFormItem(settings.metricsAddress)
+
+	for _, info := range allActions() {
+		if info.fixed {
+			continue
+		}
+		field := tview.NewInputField().SetLabel(info.description + " key").SetText(m.keys.Name(info.action))
+		settings.form.AddFormItem(field)
+		settings.keys[info.action] = field
+	}
+
+	return settings
+}
+
+// addTimerFields adds timer settings of profile, labels start with prefix.
+// Default profile has empty prefix and nil name.
+func (s *settingsForm) addTimerFields(prefix string, name *string, timer config.TimerConfig) {
+	label := func(text string) string {
+		if prefix == "" {
+			return text
+		}
+		return prefix + " " + strings.ToLower(text)
+	}
+
+	fields := timerFields{
+		name:            nil,
+		focusDuration:   newInputField(label("Focus duration"), formatConfigDuration(timer.FocusDuration)),
+		breakDuration:   newInputField(label("Break duration"), formatConfigDuration(timer.BreakDuration)),
+		hiddenFocusTime: tview.NewCheckbox().SetLabel(label("Hide focus clock")).SetChecked(timer.HiddenFocusTime),
+		flowMode:        tview.NewCheckbox().SetLabel(label("Flow mode")).SetChecked(timer.FlowMode),
+		strict:          tview.NewCheckbox().SetLabel(label("Strict mode")).SetChecked(timer.Strict),
+	}
+	if name != nil {
+		fields.name = newInputField(label("Name"), *name)
+		s.form.AddFormItem(fields.name)
+	}
+	s.form.
+		AddFormItem(fields.focusDuration).
+		AddFormItem(fields.breakDuration).
+		AddFormItem(fields.hiddenFocusTime).
+		AddFormItem(fields.flowMode).
+		AddFormItem(fields.strict)
+	s.timers = append(s.timers, fields)
+}
+
+func (s *settingsForm) addPlanFields(prefix string, plan config.Plan) {
+	fields := planFields{
+		name:  newInputField(prefix+" name", plan.Name),
+		steps: newInputField(prefix+" steps", formatPlanSteps(plan.Steps)),
+	}
+	s.form.AddFormItem(fields.name).AddFormItem(fields.steps)
+	s.plans = append(s.plans, fields)
+}
+
+func newInputField(label, text string) *tview.InputField {
+	return tview.NewInputField().SetLabel(label).SetText(text)
+}
+
+// newDropDown creates drop down with selected value.
+func newDropDown(label string, options []string, selected string) *tview.DropDown {
+	index := 0
+	for i, option := range options {
+		if option == selected {
+			index = i
+		}
+	}
+	return tview.NewDropDown().SetLabel(label).SetOptions(options, nil).SetCurrentOption(index)
+}
+
+type themeColorField struct {
+	label string
+	value *string
+}
+
+// themeColorFields lists custom colors of theme config in the order they're shown in form.
+func themeColorFields(t *config.ThemeConfig) []themeColorField {
+	return []themeColorField{
+		{"Background", &t.Background},
+		{"Text", &t.Text},
+		{"Border", &t.Border},
+		{"Contrast", &t.Contrast},
+		{"Focus", &t.Focus},
+		{"Break", &t.Break},
+		{"Active page", &t.PageActive},
+		{"Other page", &t.PageOther},
+		{"Muted", &t.Muted},
+		{"Danger", &t.Danger},
+		{"Highlight", &t.Highlight},
+		{"Overtime", &t.Overtime},
+	}
+}
+
+// saveSettings validates form, writes config file and applies it to running app.
+func (m *UIManager) saveSettings(settings *settingsForm) error {
+	timer, profiles, profile, err := settings.profiles()
+	if err != nil {
+		return err
+	}
+	plans, err := settings.plansConfig()
+	if err != nil {
+		return err
+	}
+	flowtime, err := settings.flowtime()
+	if err != nil {
+		return err
+	}
+	pauses, err := settings.pauses()
+	if err != nil {
+		return err
+	}
+	minimum, err := settings.minimum()
+	if err != nil {
+		return err
+	}
+	syncConfig, err := settings.sync()
+	if err != nil {
+		return err
+	}
+	_, themeName := settings.themeName.GetCurrentOption()
+	_, taskSource := settings.taskSource.GetCurrentOption()
+	if taskSource == taskSourceBase {
+		taskSource = ""
+	}
+
+	newConfig := config.Config{
+		Timer:    timer,
+		Profiles: profiles,
+		Profile:  profile,
+		Plans:    plans,
+		Flowtime: flowtime,
+		Pauses:   pauses,
+		Minimum:  minimum,
+		Sync:     syncConfig,
+		Tasks: config.TasksConfig{
+			Source: taskSource,
+			File:   strings.TrimSpace(settings.taskFile.GetText()),
+		},
+		API: config.APIConfig{
+			Enabled: settings.apiEnabled.IsChecked(),
+			Address: strings.TrimSpace(settings.apiAddress.GetText()),
+		},
+		Metrics: config.MetricsConfig{
+			Enabled: settings.metricsEnabled.IsChecked(),
+			Address: strings.TrimSpace(settings.metricsAddress.GetText()),
+		},
+		Theme: config.ThemeConfig{Name: themeName},
+		UI:    config.UIConfig{ShowKeyTips: settings.showKeyTips.IsChecked()},
+		Keys:  nil,
+	}
+	for i, color := range themeColorFields(&newConfig.Theme) {
+		*color.value = strings.TrimSpace(settings.colors[i].GetText())
+	}
+	if err = newConfig.Validate(); err != nil {
+		return err
+	}
+
+	keySpecs := make(map[string]string, len(settings.keys))
+	for action, field := range settings.keys {
+		keySpecs[string(action)] = field.GetText()
+	}
+	keys, err := NewKeyBindings(keySpecs)
+	if err != nil {
+		return err
+	}
+	// reloaded file has nil keys without overrides, keep it equal to saved config
+	if overrides := keys.Overrides(); len(overrides) > 0 {
+		newConfig.Keys = overrides
+	}
+
+	theme, err := NewTheme(newConfig.Theme)
+	if err != nil {
+		return err
+	}
+
+	if err = config.Save(&newConfig); err != nil {
+		return err
+	}
+
+	message := m.theme.Highlight.Wrap("Saved")
+	if m.restartNeeded(&newConfig, theme) {
+		message += ", some changes apply after restart"
+	}
+
+	m.applyConfig(&newConfig, keys, theme)
+	m.AddPageAndSwitch(NewPageComponent(settingsPage, true, m.renderSettingsGrid(message)))
+	return nil
+}
+
+// profiles returns timer of default profile, named profiles and name of the active one.
+// Active profile that was removed is replaced with default.
+func (s *settingsForm) profiles() (config.TimerConfig, []config.Profile, string, error) {
+	timer, err := s.timers[0].timer()
+	if err != nil {
+		return config.TimerConfig{}, nil, "", err
+	}
+
+	var profiles []config.Profile
+	for _, fields := range s.timers[1:] {
+		name := strings.TrimSpace(fields.name.GetText())
+		if name == "" {
+			continue
+		}
+		profileTimer, err := fields.timer()
+		if err != nil {
+			return config.TimerConfig{}, nil, "", fmt.Errorf("profile %s: %w", name, err)
+		}
+		profiles = append(profiles, config.Profile{Name: name, Timer: profileTimer})
+	}
+
+	// options of drop down are default profile and named profiles in form order
+	active := ""
+	if index, _ := s.profile.GetCurrentOption(); index > 0 {
+		active = strings.TrimSpace(s.timers[index].name.GetText())
+	}
+	return timer, profiles, active, nil
+}
+
+func (f timerFields) timer() (config.TimerConfig, error) {
+	focusDuration, err := time.ParseDuration(strings.TrimSpace(f.focusDuration.GetText()))
+	if err != nil {
+		return config.TimerConfig{}, fmt.Errorf("focus duration: %w", err)
+	}
+	breakDuration, err := time.ParseDuration(strings.TrimSpace(f.breakDuration.GetText()))
+	if err != nil {
+		return config.TimerConfig{}, fmt.Errorf("break duration: %w", err)
+	}
+	return config.TimerConfig{
+		FocusDuration:   focusDuration,
+		BreakDuration:   breakDuration,
+		HiddenFocusTime: f.hiddenFocusTime.IsChecked(),
+		FlowMode:        f.flowMode.IsChecked(),
+		Strict:          f.strict.IsChecked(),
+	}, nil
+}
+
+func (s *settingsForm) plansConfig() ([]config.Plan, error) {
+	var plans []config.Plan
+	for _, fields := range s.plans {
+		name := strings.TrimSpace(fields.name.GetText())
+		if name == "" {
+			continue
+		}
+		steps, err := parsePlanSteps(fields.steps.GetText())
+		if err != nil {
+			return nil, fmt.Errorf("plan %s: %w", name, err)
+		}
+		plans = append(plans, config.Plan{Name: name, Steps: steps})
+	}
+	return plans, nil
+}
+
+func (s *settingsForm) flowtime() (config.FlowtimeConfig, error) {
+	divisor, err := parseOptionalInt(s.flowDivisor.GetText())
+	if err != nil {
+		return config.FlowtimeConfig{}, fmt.Errorf("flowtime divisor: %w", err)
+	}
+	minBreak, err := parseOptionalDuration(s.flowMinBreak.GetText())
+	if err != nil {
+		return config.FlowtimeConfig{}, fmt.Errorf("flowtime min break: %w", err)
+	}
+	maxBreak, err := parseOptionalDuration(s.flowMaxBreak.GetText())
+	if err != nil {
+		return config.FlowtimeConfig{}, fmt.Errorf("flowtime max break: %w", err)
+	}
+	rules, err := parseFlowtimeRules(s.flowRules.GetText())
+	if err != nil {
+		return config.FlowtimeConfig{}, fmt.Errorf("flowtime rules: %w", err)
+	}
+	return config.FlowtimeConfig{Divisor: divisor, MinBreak: minBreak, MaxBreak: maxBreak, Rules: rules}, nil
+}
+
+func (s *settingsForm) pauses() (config.PausesConfig, error) {
+	maxCount, err := parseOptionalInt(s.pauseMaxCount.GetText())
+	if err != nil {
+		return config.PausesConfig{}, fmt.Errorf("max pauses: %w", err)
+	}
+	maxTime, err := parseOptionalDuration(s.pauseMaxTime.GetText())
+	if err != nil {
+		return config.PausesConfig{}, fmt.Errorf("max pause time: %w", err)
+	}
+	return config.PausesConfig{MaxCount: maxCount, MaxTime: maxTime, Void: s.pauseVoid.IsChecked()}, nil
+}
+
+func (s *settingsForm) minimum() (config.MinimumConfig, error) {
+	duration, err := parseOptionalDuration(s.minDuration.GetText())
+	if err != nil {
+		return config.MinimumConfig{}, fmt.Errorf("minimum session: %w", err)
+	}
+	percent, err := parseOptionalInt(s.minPercent.GetText())
+	if err != nil {
+		return config.MinimumConfig{}, fmt.Errorf("minimum percent: %w", err)
+	}
+	return config.MinimumConfig{Duration: duration, Percent: percent, Discard: s.minDiscard.IsChecked()}, nil
+}
+
+func (s *settingsForm) sync() (config.SyncConfig, error) {
+	projects, err := parseProjects(s.syncProjects.GetText())
+	if err != nil {
+		return config.SyncConfig{}, fmt.Errorf("sync task projects: %w", err)
+	}
+	_, service := s.syncService.GetCurrentOption()
+	if service == syncOff {
+		service = ""
+	}
+	return config.SyncConfig{
+		Service:       service,
+		BaseURL:       strings.TrimSpace(s.syncBaseURL.GetText()),
+		Token:         strings.TrimSpace(s.syncToken.GetText()),
+		Workspace:     strings.TrimSpace(s.syncWorkspace.GetText()),
+		Project:       strings.TrimSpace(s.syncProject.GetText()),
+		Projects:      projects,
+		AfterPomodoro: s.syncAfterPomodoro.IsChecked(),
+	}, nil
+}
+
+// formatConfigDuration prints duration like "25m" instead of "25m0s".
+func formatConfigDuration(d time.Duration) string {
+	str := d.String()
+	if strings.HasSuffix(str, "m0s") {
+		str = strings.TrimSuffix(str, "0s")
+	}
+	if strings.HasSuffix(str, "h0m") {
+		str = strings.TrimSuffix(str, "0m")
+	}
+	return str
+}
@@ -11,14 +11,18 @@ import (
 	"github.com/rivo/tview"
 )
 
-const statisticsPageSize = 7
+const (
+	statisticsPageSize = 7
+	// deleteColumn holds delete button of session row.
+	deleteColumn = 4
+)
 
 func (m *UIManager) NewDetailStats(start, end int) *Page {
-	return NewPageComponent(detailStatsPage, true, m.renderDetailStatsPage(start, end))
+	return NewPageComponent(detailStatsPage, true, m.renderDetailStatsPage(start, end)).SetFullWidth()
 }
 
 func (m *UIManager) NewInsertDetailPage(start, end int) *Page {
-	return NewPageComponent(insertStatsPage, true, m.renderInsertStatsPage(start, end))
+	return NewPageComponent(insertStatsPage, true, m.renderInsertStatsPage(start, end)).SetFullWidth()
 }
 
 func (m *UIManager) renderDetailStatsPage(args ...any) func() tview.Primitive {
@@ -50,7 +54,7 @@ func (m *UIManager) renderDetailStatsPage(args ...any) func() tview.Primitive {
 
 		grid := tview.NewGrid().
 			SetRows(1, 0, 1).
-			SetColumns(0, 23, 23, 0)
+			SetColumns(0, 28, 28, 0)
 
 		grid.AddItem(text, 0, 1, 1, 2, 0, 0, false)
 		grid.AddItem(table, 1, 1, 1, 2, 0, 0, true)
@@ -101,9 +105,14 @@ func (m *UIManager) captureStatsInput(table *tview.Table,
 				newIndx := (prevIndx + 1) % len(buttons)
 				m.ui.SetFocus(buttons[newIndx])
 			}
-		case tcell.KeyCtrlA:
-			m.AddPageAndSwitch(m.NewInsertDetailPage(-1, -1))
 		default:
+			if m.keys.Match(actionInsertRecord, event) {
+				m.AddPageAndSwitch(m.NewInsertDetailPage(-1, -1))
+			}
+			if m.keys.Match(actionExportICS, event) {
+				m.exportCalendar()
+				return nil
+			}
 		}
 		return event
 	}
@@ -155,7 +164,7 @@ func (m *UIManager) newStatsButtons(start, end int, pomodoros []*Pomodoro) []*tv
 
 func (m *UIManager) newStatsTable(start, end int, pomodoros []*Pomodoro) *tview.Table {
 	table := tview.NewTable().SetBorders(true)
-	headers := []string{"Date", "Time", "Minutes", "Action"}
+	headers := []string{"Date", "Time", "Minutes", "Pauses", "Action"}
 
 	for col, header := range headers {
 		table.SetCell(0, col, tview.NewTableCell(header).SetAlign(tview.AlignCenter))
@@ -170,37 +179,70 @@ func (m *UIManager) newStatsTable(start, end int, pomodoros []*Pomodoro) *tview.
 
 		table.SetCell(row, 0, tview.NewTableCell(dateStr).SetAlign(tview.AlignCenter))
 		table.SetCell(row, 1, tview.NewTableCell(timeStr).SetAlign(tview.AlignCenter))
-		table.SetCell(row, 2, tview.NewTableCell(strconv.Itoa(pmdr.SecondsDuration/60)).SetAlign(tview.AlignCenter))
-		table.SetCell(row, 3, tview.NewTableCell("[red] Delete [-]").SetAlign(tview.AlignCenter).SetSelectable(true))
+		table.SetCell(row, 2, tview.NewTableCell(m.formatMinutes(pmdr)).SetAlign(tview.AlignCenter))
+		table.SetCell(row, 3, tview.NewTableCell(m.formatPauses(pmdr)).SetAlign(tview.AlignCenter))
+		table.SetCell(row, deleteColumn, tview.NewTableCell(m.theme.Danger.Wrap(" Delete ")).
+			SetAlign(tview.AlignCenter).SetSelectable(true))
 	}
 
 	table.SetInputCapture(m.captureTableInput(table, pomodoros))
 	return table
 }
 
+// formatMinutes prints duration of session with marker of manual adjustment
+// and of session that didn't run till the end, e.g. "30 (+5)" or "12 skipped".
+func (m *UIManager) formatMinutes(pomodoro *Pomodoro) string {
+	minutes := strconv.Itoa(pomodoro.SecondsDuration / 60)
+	if adjusted := pomodoro.AdjustedSeconds / 60; adjusted != 0 {
+		minutes += m.theme.Muted.Wrap(fmt.Sprintf(" (%+d)", adjusted))
+	}
+	if pomodoro.Outcome != OutcomeCompleted {
+		minutes += m.theme.Muted.Wrap(" " + string(pomodoro.Outcome))
+	}
+	return minutes
+}
+
+// formatPauses prints pause count and paused time, e.g. "2 / 7m".
+// Session paused over the limit is marked, voided one is marked in minutes column.
+func (m *UIManager) formatPauses(pomodoro *Pomodoro) string {
+	if pomodoro.Pauses == 0 {
+		return "-"
+	}
+
+	paused := time.Duration(pomodoro.PausedSeconds) * time.Second
+	pauses := fmt.Sprintf("%d / %dm", pomodoro.Pauses, int(paused.Minutes()))
+	if pomodoro.Outcome != OutcomeVoided && m.pomodoroTracker.PauseLimit().Exceeded(pomodoro.Pauses, paused) {
+		return m.theme.Danger.Wrap(pauses + " !")
+	}
+	return pauses
+}
+
 func (m *UIManager) captureTableInput(table *tview.Table, pomdoro []*Pomodoro) func(*tcell.EventKey) *tcell.EventKey {
 	handleEnterKey := func(table *tview.Table, pomodoro []*Pomodoro, col int) {
-		if len(pomodoro) > 0 && col != 3 {
-			table.Select(1, 3).SetSelectable(true, true)
-		} else if col == 3 {
+		if len(pomodoro) > 0 && col != deleteColumn {
+			table.Select(1, deleteColumn).SetSelectable(true, true)
+		} else if col == deleteColumn {
 			table.Select(0, 0).SetSelectable(false, false)
 		}
 	}
 
 	return func(event *tcell.EventKey) *tcell.EventKey {
 		row, col := table.GetSelection()
+		if m.keys.Match(actionDeleteRecord, event) {
+			if col == deleteColumn && row > 0 {
+				m.removePomodoro(pomdoro, row-1)
+			}
+			return nil
+		}
+
 		switch event.Key() {
 		case tcell.KeyEnter:
 			handleEnterKey(table, pomdoro, col)
 		case tcell.KeyDown, tcell.KeyUp:
 			m.handleVerticalNavigation(table, row, col, event.Key())
 		case tcell.KeyLeft, tcell.KeyRight:
-			if col != 3 {
-				table.Select(row, 3)
-			}
-		case tcell.KeyCtrlY:
-			if col == 3 && row > 0 {
-				m.removePomodoro(pomdoro, row-1)
+			if col != deleteColumn {
+				table.Select(row, deleteColumn)
 			}
 		case tcell.KeyEscape:
 			table.Select(0, 0).SetSelectable(false, false)
@@ -238,9 +280,24 @@ func (m *UIManager) removePomodoro(pomodoros []*Pomodoro, pomodoroIndx int) {
 	m.AddPageAndSwitch(m.NewDetailStats(-1, -1))
 }
 
+// exportCalendar writes sessions to .ics file and shows where it is.
+func (m *UIManager) exportCalendar() {
+	path, err := m.pomodoroTracker.ExportCalendar()
+	if err != nil {
+		m.logger.Error("can't export calendar", slog.Any("error", err))
+		m.showNotice(m.theme.Danger.Wrap("Can't export calendar"))
+		return
+	}
+	m.showNotice(m.theme.Highlight.Wrap("Calendar saved to " + tview.Escape(path)))
+}
+
+// totalDuration sums sessions that count in hours, like summary page.
 func (m *UIManager) totalDuration(pomodoros []*Pomodoro) string {
 	var total int
 	for _, t := range pomodoros {
+		if !t.counted() {
+			continue
+		}
 		total += t.SecondsDuration
 	}
 	res := time.Duration(total) * time.Second
@@ -282,7 +339,7 @@ func (m *UIManager) renderInsertStatsPage(args ...any) func() tview.Primitive {
 
 		grid := tview.NewGrid().
 			SetRows(1, 3, 0, 1, 1).
-			SetColumns(0, 23, 23, 0).
+			SetColumns(0, 28, 28, 0).
 			SetBorders(true)
 
 		grid.AddItem(text, 0, 1, 1, 2, 0, 0, false)
@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"path/filepath"
 	"strconv"
 
+	"github.com/arevbond/PomoTrack/config"
 	"github.com/rivo/tview"
 )
 
@@ -15,57 +17,124 @@ func (m *UIManager) NewSummaryPage() *Page {
 		return nil
 	}
 
-	render := m.renderSummaryStatsPage(
-		m.pomodoroTracker.Hours(pomodoros),
-		m.pomodoroTracker.CountDays(pomodoros),
-		m.pomodoroTracker.HoursInWeek(pomodoros),
-	)
+	breaks, err := m.pomodoroTracker.Breaks()
+	if err != nil {
+		m.logger.Error("can't get breaks", slog.Any("error", err))
+		return nil
+	}
+
+	render := m.renderSummaryStatsPage(summaryStats{
+		totalHours:     m.pomodoroTracker.Hours(pomodoros),
+		totalDays:      m.pomodoroTracker.CountDays(pomodoros),
+		weekdayHours:   m.pomodoroTracker.HoursInWeek(pomodoros),
+		overtimeHours:  m.pomodoroTracker.OvertimeHours(pomodoros),
+		breakHours:     m.pomodoroTracker.Hours(breaks),
+		completionRate: m.pomodoroTracker.CompletionRate(pomodoros),
+		repoHours:      m.pomodoroTracker.HoursByRepo(pomodoros),
+		profileHours:   m.pomodoroTracker.HoursByProfile(pomodoros),
+	})
 
 	return NewPageComponent(summaryStatsPage, true, render)
 }
 
-func (m *UIManager) renderSummaryStatsPage(args ...any) func() tview.Primitive {
-	totalHours, ok := args[0].(float64)
-	if !ok {
-		m.logger.Error("can't extract argument for rendering summary stats prettyPageName",
-			slog.String("func", "render summary stats prettyPageName"))
-		return nil
-	}
-	totalDays, ok := args[1].(int)
-	if !ok {
-		m.logger.Error("can't extract argument for rendering summary stats prettyPageName",
-			slog.String("func", "render summary stats prettyPageName"))
-		return nil
-	}
-	weekdayHours, ok := args[2].([7]int)
-	if !ok {
-		m.logger.Error("can't extract argument for rendering summary stats prettyPageName",
-			slog.String("func", "render summary stats prettyPageName"))
-		return nil
-	}
+// summaryStats is what summary page shows for all time.
+type summaryStats struct {
+	totalHours     float64
+	totalDays      int
+	weekdayHours   [7]int
+	overtimeHours  float64
+	breakHours     float64
+	completionRate float64
+	repoHours      []RepoHours
+	profileHours   []ProfileHours
+}
 
+func (m *UIManager) renderSummaryStatsPage(stats summaryStats) func() tview.Primitive {
 	return func() tview.Primitive {
 		table := tview.NewTable().
 			SetBorders(true)
 
-		table.SetCell(0, 0, tview.NewTableCell("Hours focused"))
-		table.SetCell(1, 0, tview.NewTableCell(fmt.Sprintf("%.2f", totalHours)).SetAlign(tview.AlignCenter))
-
-		table.SetCell(0, 1, tview.NewTableCell("Days accessed"))
-		table.SetCell(1, 1, tview.NewTableCell(strconv.Itoa(totalDays)).SetAlign(tview.AlignCenter))
+		rows := []struct {
+			name  string
+			value string
+		}{
+			{"Hours focused", fmt.Sprintf("%.2f", stats.totalHours)},
+			{"Days accessed", strconv.Itoa(stats.totalDays)},
+			{"Overtime hours", fmt.Sprintf("%.2f", stats.overtimeHours)},
+			{"Break hours", fmt.Sprintf("%.2f", stats.breakHours)},
+			{"Completion rate", fmt.Sprintf("%.0f%%", stats.completionRate*100)},
+		}
+		for row, r := range rows {
+			table.SetCell(row, 0, tview.NewTableCell(r.name).SetExpansion(1))
+			table.SetCell(row, 1, tview.NewTableCell(r.value).SetAlign(tview.AlignCenter).SetExpansion(1))
+		}
 
 		bar := tview.NewTextView().
 			SetDynamicColors(true).
-			SetText("\n\n\n" + CreateBarGraph(weekdayHours))
+			SetText("\n\n\n" + CreateBarGraph(stats.weekdayHours, m.theme.Highlight))
 
 		grid := tview.NewGrid().
-			SetRows(5, 0).
-			SetColumns(0, 40, 0).
+			SetColumns(0, 46, 0).
 			SetBorders(true)
 
+		// breakdowns go between totals and bar graph, each one only if there is something to split
+		heights := []int{11}
 		grid.AddItem(table, 0, 1, 1, 1, 0, 0, false)
-		grid.AddItem(bar, 1, 1, 1, 1, 0, 0, false)
+		profileHours := stats.profileHours
+		if len(profileHours) > 1 || (len(profileHours) == 1 && profileHours[0].Profile != config.DefaultProfile) {
+			profileTable := m.profileHoursTable(profileHours)
+			grid.AddItem(profileTable, len(heights), 1, 1, 1, 0, 0, false)
+			heights = append(heights, min(profileTable.GetRowCount(), maxProfileRows))
+		}
+		if len(stats.repoHours) > 0 {
+			repoTable := m.repoHoursTable(stats.repoHours)
+			grid.AddItem(repoTable, len(heights), 1, 1, 1, 0, 0, false)
+			heights = append(heights, min(repoTable.GetRowCount(), maxRepoRows))
+		}
+		grid.AddItem(bar, len(heights), 1, 1, 1, 0, 0, false)
+		grid.SetRows(append(heights, 0)...)
 
 		return grid
 	}
 }
+
+// maxProfileRows is height of profiles breakdown, longer breakdown is scrolled.
+const maxProfileRows = 4
+
+// profileHoursTable lists hours focused with each timer profile.
+func (m *UIManager) profileHoursTable(profileHours []ProfileHours) *tview.Table {
+	table := tview.NewTable()
+	for row, profile := range profileHours {
+		table.SetCell(row, 0, tview.NewTableCell(tview.Escape(profile.Profile)).SetExpansion(1))
+		table.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%.2f", profile.Hours)).SetAlign(tview.AlignCenter).
+			SetExpansion(1))
+	}
+	return table
+}
+
+// maxRepoRows is height of repositories breakdown, longer breakdown is scrolled.
+const maxRepoRows = 6
+
+// repoHoursTable lists hours focused in each git repository and its branches.
+func (m *UIManager) repoHoursTable(repoHours []RepoHours) *tview.Table {
+	table := tview.NewTable()
+
+	row := 0
+	for _, repo := range repoHours {
+		table.SetCell(row, 0, tview.NewTableCell("[::b]"+tview.Escape(filepath.Base(repo.Repo))).SetExpansion(1))
+		table.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%.2f", repo.Hours)).SetAlign(tview.AlignCenter).
+			SetExpansion(1))
+		row++
+		for _, branch := range repo.Branches {
+			name := branch.Branch
+			if name == "" {
+				name = "?"
+			}
+			table.SetCell(row, 0, tview.NewTableCell("  "+m.theme.Muted.Wrap(tview.Escape(name))).SetExpansion(1))
+			table.SetCell(row, 1, tview.NewTableCell(fmt.Sprintf("%.2f", branch.Hours)).
+				SetAlign(tview.AlignCenter).SetExpansion(1))
+			row++
+		}
+	}
+	return table
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const suspendPage = "Suspend"
+
+// showSuspendOffer tells that focus session ran out while computer was asleep
+// and offers to remove slept time from the recorded session.
+func (m *UIManager) showSuspendOffer(event SuspendEvent) {
+	const keepButton, subtractButton = "Keep", "Subtract sleep"
+
+	text := fmt.Sprintf("Focus session ended at %s while computer was asleep.\n"+
+		"%s of it passed in suspend.", event.At.Format("15:04"), formatConfigDuration(event.Asleep.Round(time.Second)))
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{keepButton, subtractButton}).
+		SetDoneFunc(func(_ int, label string) {
+			m.closeOverlay(suspendPage)
+			if label != subtractButton {
+				return
+			}
+			if err := m.pomodoroTracker.SubtractFromPomodoro(event.SessionID, event.Asleep); err != nil {
+				m.logger.Error("can't adjust pomodoro", slog.Any("error", err))
+				m.showNotice(m.theme.Danger.Wrap(tview.Escape(err.Error())))
+				return
+			}
+			m.showNotice(m.theme.Highlight.Wrap("Recorded time adjusted"))
+		})
+
+	m.pages.AddPage(suspendPage, modal, true, true)
+	m.ui.SetFocus(modal)
+}
+
+// raiseSuspendOffer keeps unanswered offer over page opened after it, e.g. next step of plan.
+func (m *UIManager) raiseSuspendOffer() {
+	if !m.pages.HasPage(suspendPage) {
+		return
+	}
+	m.pages.ShowPage(suspendPage).SendToFront(suspendPage)
+	if _, front := m.pages.GetFrontPage(); front != nil {
+		m.ui.SetFocus(front)
+	}
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
 )
 
 type PageName string
@@ -13,6 +16,9 @@ const (
 	activeBreakPage PageName = "Active-Break"
 	pauseBreakPage  PageName = "Stop-Break"
 
+	activeStopwatchPage PageName = "Active-Flowtime"
+	pauseStopwatchPage  PageName = "Stop-Flowtime"
+
 	allTasksPage   PageName = "All-Tasks-Pages"
 	addNewTaskPage PageName = "Add-New-Task"
 	deleteTaskPage PageName = "Delete-Task-Page"
@@ -21,31 +27,46 @@ const (
 	insertStatsPage PageName = "Insert-Statistics"
 
 	summaryStatsPage PageName = "Summary-Statistics"
+
+	settingsPage PageName = "Settings"
 )
 
 func constructAllowedTransitions() map[PageName][]PageName {
 	return map[PageName][]PageName{
-		pauseFocusPage:   {detailStatsPage, pauseBreakPage, summaryStatsPage, allTasksPage},
-		pauseBreakPage:   {detailStatsPage, pauseFocusPage, summaryStatsPage, allTasksPage},
-		detailStatsPage:  {pauseFocusPage, pauseBreakPage, insertStatsPage, summaryStatsPage, allTasksPage},
-		summaryStatsPage: {pauseFocusPage, pauseBreakPage, insertStatsPage, detailStatsPage, allTasksPage},
-		allTasksPage:     {pauseFocusPage, pauseBreakPage, detailStatsPage, summaryStatsPage},
+		pauseFocusPage: {detailStatsPage, pauseBreakPage, pauseStopwatchPage, summaryStatsPage, allTasksPage,
+			settingsPage},
+		pauseBreakPage: {detailStatsPage, pauseFocusPage, pauseStopwatchPage, summaryStatsPage, allTasksPage,
+			settingsPage},
+		pauseStopwatchPage: {detailStatsPage, pauseFocusPage, pauseBreakPage, summaryStatsPage, allTasksPage,
+			settingsPage},
+		detailStatsPage: {pauseFocusPage, pauseBreakPage, pauseStopwatchPage, insertStatsPage, summaryStatsPage,
+			allTasksPage, settingsPage},
+		summaryStatsPage: {pauseFocusPage, pauseBreakPage, pauseStopwatchPage, insertStatsPage, detailStatsPage,
+			allTasksPage, settingsPage},
+		allTasksPage: {pauseFocusPage, pauseBreakPage, pauseStopwatchPage, detailStatsPage, summaryStatsPage,
+			settingsPage},
+		settingsPage: {pauseFocusPage, pauseBreakPage, pauseStopwatchPage, detailStatsPage, summaryStatsPage,
+			allTasksPage},
 	}
 }
 
-func (m *UIManager) constructKeyPageMap() map[tcell.Key]*Page {
+func (m *UIManager) constructKeyPageMap() map[Action]*Page {
 	pauseFocus := m.NewPausePage(FocusTimer)
 	pauseBreak := m.NewPausePage(BreakTimer)
+	pauseStopwatch := m.NewPausePage(StopwatchTimer)
 	tasksPage := m.NewTasksPage()
 	summaryPage := m.NewSummaryPage()
 	detailPage := m.NewDetailStats(-1, -1)
-
-	return map[tcell.Key]*Page{
-		tcell.KeyF1: pauseFocus,
-		tcell.KeyF2: pauseBreak,
-		tcell.KeyF3: tasksPage,
-		tcell.KeyF4: summaryPage,
-		tcell.KeyF5: detailPage,
+	settings := m.NewSettingsPage()
+
+	return map[Action]*Page{
+		actionFocusPage:    pauseFocus,
+		actionBreakPage:    pauseBreak,
+		actionFlowtimePage: pauseStopwatch,
+		actionTasksPage:    tasksPage,
+		actionSummaryPage:  summaryPage,
+		actionDetailPage:   detailPage,
+		actionSettingsPage: settings,
 	}
 }
 
@@ -70,11 +91,25 @@ func (m *UIManager) canSwitchTo(targetPage PageName) bool {
 
 func (m *UIManager) keyboardEvents(event *tcell.EventKey) *tcell.EventKey {
 	if event.Key() == tcell.KeyCtrlC {
-		m.pomodoroTracker.FinishRunningPomodoro()
+		m.pomodoroTracker.FinishRunningPomodoro(m.stateManager.MinFocus())
 		m.ui.Stop()
 	}
 
-	targetPage, exists := m.keyPageMapping[event.Key()]
+	// don't steal printable keys from input fields
+	if _, ok := m.ui.GetFocus().(*tview.InputField); ok && event.Key() == tcell.KeyRune {
+		return event
+	}
+
+	switch {
+	case m.keys.Match(actionHelp, event):
+		m.toggleHelp()
+		return nil
+	case m.keys.Match(actionToggleTips, event):
+		m.toggleKeyTips()
+		return nil
+	}
+
+	targetPage, exists := m.pageForKey(event)
 	if !exists || !m.canSwitchTo(targetPage.name) {
 		return event
 	}
@@ -89,36 +124,76 @@ func (m *UIManager) keyboardEvents(event *tcell.EventKey) *tcell.EventKey {
 		targetPage = m.NewTasksPage()
 	case pauseBreakPage:
 		targetPage = m.NewPausePage(BreakTimer)
+	case pauseStopwatchPage:
+		targetPage = m.NewPausePage(StopwatchTimer)
+	case settingsPage:
+		targetPage = m.NewSettingsPage() // need for reload config values
 	}
 
 	m.AddPageAndSwitch(targetPage)
 	return nil
 }
 
+func (m *UIManager) pageForKey(event *tcell.EventKey) (*Page, bool) {
+	for action, page := range m.keyPageMapping {
+		if m.keys.Match(action, event) {
+			return page, true
+		}
+	}
+	return nil, false
+}
+
 func (m *UIManager) InitStateAndKeyboardHandling() {
 	stopRefreshing := make(chan struct{})
 	m.setKeyboardEvents()
 	go m.listenToStateChanges(stopRefreshing)
 }
 
+// AddStateListener sends every state event to ch, it has to be called before state changes
+// are listened. Listener must receive without delay, state changes wait for it.
+func (m *UIManager) AddStateListener(ch chan<- StateEvent) {
+	m.stateListeners = append(m.stateListeners, ch)
+}
+
 func (m *UIManager) listenToStateChanges(stopRefreshing chan struct{}) {
+	// paused session can be finished too, then there is no active page to stop
+	refreshing := false
 	for event := range m.stateUpdates {
+		m.plans.HandleStateEvent(event)
 		m.statePomodoroUpdates <- event
+		for _, listener := range m.stateListeners {
+			listener <- event
+		}
+		m.events.Publish(event)
 
 		switch event.NewState {
 		case StateActive:
+			refreshing = true
 			m.ui.QueueUpdateDraw(func() {
 				m.AddPageAndSwitch(m.NewActivePage(event.TimerType, stopRefreshing))
 			})
 
+		case StateOvertime:
+			go playEndSound()
+
 		case StatePaused, StateFinished:
-			stopRefreshing <- struct{}{}
+			if refreshing {
+				stopRefreshing <- struct{}{}
+				refreshing = false
+			}
 
 			if event.NewState == StatePaused {
 				m.handleStatePaused(event.TimerType)
 			} else if event.NewState == StateFinished {
 				m.handleStateFinished(event.TimerType)
 			}
+
+			if event.TooShort {
+				m.ui.QueueUpdateDraw(func() {
+					m.showNotice(m.theme.Muted.Wrap(fmt.Sprintf("Session shorter than %s isn't counted",
+						formatConfigDuration(m.stateManager.MinFocus()))))
+				})
+			}
 		}
 	}
 }
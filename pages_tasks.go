@@ -44,7 +44,7 @@ func (m *UIManager) allTasksGrid(tasks []*Task) tview.Primitive {
 			name = fmt.Sprintf("[::bu]%s[-]", name)
 		}
 		if task.IsComplete {
-			name = fmt.Sprintf("[gray]%s[-]", name)
+			name = m.theme.Muted.Wrap(name)
 		}
 		list = list.AddItem(name, fmt.Sprintf("%d/%d", task.PomodorosCompleted, task.PomodorosRequired),
 			shortCut, m.changeActiveTask(task))
@@ -57,10 +57,10 @@ func (m *UIManager) allTasksGrid(tasks []*Task) tview.Primitive {
 
 	grid.AddItem(list, 0, 1, 1, 1, 0, 0, true)
 	grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyCtrlA:
+		switch {
+		case m.keys.Match(actionAddTask, event):
 			m.AddPageAndSwitch(m.NewTaskCreationPage())
-		case tcell.KeyCtrlD:
+		case m.keys.Match(actionDeleteTask, event):
 			m.AddPageAndSwitch(m.NewTaskDeletionPage())
 		}
 		return event
@@ -153,7 +153,7 @@ func (m *UIManager) renderDeletionTaskGrid() func() tview.Primitive {
 				m.AddPageAndSwitch(m.NewTasksPage())
 				return
 			}
-			err = m.taskTracker.DeleteTask(tasks[indx].ID)
+			err = m.taskTracker.DeleteTask(tasks[indx])
 			if err != nil {
 				m.logger.Error("can't delete task", slog.Int("index", indx), slog.Any("error", err))
 				m.AddPageAndSwitch(m.NewTasksPage())
@@ -210,6 +210,9 @@ func (m *UIManager) saveNewTask(form *tview.Form, isFirstTask bool) func() {
 			PomodorosRequired:  pomodorosRequired,
 			IsActive:           isFirstTask,
 			CreateAt:           time.Now(),
+			UUID:               "",
+			CompleteAt:         time.Time{},
+			line:               "",
 		}
 		if task.PomodorosCompleted == task.PomodorosRequired {
 			task.IsComplete = true
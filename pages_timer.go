@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/arevbond/PomoTrack/config"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -25,14 +26,17 @@ func (m *UIManager) NewActivePage(timerType TimerType, stopSignal chan struct{})
 	switch timerType {
 	case FocusTimer:
 		pageName = activeFocusPage
-		render = m.renderActivePage("red", "Time to focus", FocusTimer)
+		render = m.renderActivePage(m.theme.Focus, "Time to focus", FocusTimer)
 	case BreakTimer:
 		pageName = activeBreakPage
-		render = m.renderActivePage("green", "Time to break", BreakTimer)
+		render = m.renderActivePage(m.theme.Break, "Time to break", BreakTimer)
+	case StopwatchTimer:
+		pageName = activeStopwatchPage
+		render = m.renderFlowtimeActivePage()
 	}
 
 	go m.updateUIWithTicker(stopSignal)
-	return NewPageComponent(pageName, true, render)
+	return NewPageComponent(pageName, true, render).SetFullWidth()
 }
 
 func (m *UIManager) updateUIWithTicker(quit chan struct{}) {
@@ -59,6 +63,9 @@ func (m *UIManager) NewPausePage(timerType TimerType) *Page {
 	case FocusTimer:
 		pageName = pauseFocusPage
 		render = m.renderPausePage("Pomodoro", FocusTimer)
+	case StopwatchTimer:
+		pageName = pauseStopwatchPage
+		render = m.renderFlowtimePausePage()
 	}
 	return NewPageComponent(pageName, true, render)
 }
@@ -75,6 +82,10 @@ func (m *UIManager) handleStatePaused(timerType TimerType) {
 		m.ui.QueueUpdateDraw(func() {
 			m.AddPageAndSwitch(m.NewPausePage(BreakTimer))
 		})
+	case StopwatchTimer:
+		m.ui.QueueUpdateDraw(func() {
+			m.AddPageAndSwitch(m.NewPausePage(StopwatchTimer))
+		})
 	}
 }
 
@@ -82,7 +93,7 @@ func (m *UIManager) handleStateFinished(timerType TimerType) {
 	go playEndSound()
 
 	switch timerType {
-	case FocusTimer:
+	case FocusTimer, StopwatchTimer:
 		m.ui.QueueUpdateDraw(func() {
 			m.AddPageAndSwitch(m.NewPausePage(BreakTimer))
 		})
@@ -125,11 +136,11 @@ func (m *UIManager) renderPausePage(args ...any) func() tview.Primitive {
 			SetText(formatDuration(m.stateManager.timeToFinish(timerType)))
 
 		startButton := tview.NewButton("▶ Start").SetSelectedFunc(func() {
-			m.stateManager.SetState(StateActive, timerType)
+			m.changeState(StateActive, timerType)
 		})
 
 		grid := tview.NewGrid().
-			SetRows(0, 1, 3, 1, 0, 1).
+			SetRows(0, 1, 3, 1, 1, 0, 1).
 			SetColumns(0, 0, 15, 0, 0).
 			SetBorders(true)
 
@@ -137,15 +148,124 @@ func (m *UIManager) renderPausePage(args ...any) func() tview.Primitive {
 		grid.AddItem(durationText, 2, 2, 1, 1, 0, 0, false)
 		grid.AddItem(startButton, 3, 2, 1, 1, 0, 0, true)
 
+		if status, ok := m.plans.Status(); ok {
+			planText := tview.NewTextView().
+				SetDynamicColors(true).
+				SetTextAlign(tview.AlignCenter).
+				SetText(tview.Escape(planStatusText(status)))
+			grid.AddItem(planText, 4, 1, 1, 3, 0, 0, false)
+		}
+
+		if len(m.config.Profiles) > 0 {
+			profileText := tview.NewTextView().
+				SetDynamicColors(true).
+				SetTextAlign(tview.AlignCenter).
+				SetText(m.theme.Muted.Wrap(tview.Escape(fmt.Sprintf("%s (%s)",
+					m.stateManager.Profile(), m.keys.Name(actionNextProfile)))))
+			grid.AddItem(profileText, 6, 1, 1, 3, 0, 0, false)
+		}
+
+		grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch {
+			case m.keys.Match(actionStartPause, event):
+				m.changeState(StateActive, timerType)
+				return nil
+			case m.keys.Match(actionNextProfile, event):
+				m.nextProfile()
+				return nil
+			case m.keys.Match(actionStartPlan, event):
+				m.showPlans()
+				return nil
+			case m.keys.Match(actionAbortPlan, event):
+				m.abortPlan()
+				return nil
+			case m.adjustKey(timerType, event):
+				return nil
+			}
+			return event
+		})
+
 		return grid
 	}
 }
 
+// nextProfile switches timer to the next profile from config and saves the choice.
+func (m *UIManager) nextProfile() {
+	names := m.config.ProfileNames()
+	if len(names) < 2 {
+		return
+	}
+
+	next := names[0]
+	for i, name := range names {
+		if name == m.config.ActiveProfile() {
+			next = names[(i+1)%len(names)]
+		}
+	}
+
+	cfg := *m.config
+	cfg.Profile = next
+	if next == config.DefaultProfile {
+		cfg.Profile = ""
+	}
+	if err := config.Save(&cfg); err != nil {
+		m.logger.Error("can't save active profile", slog.Any("error", err))
+	}
+
+	m.config = &cfg
+	m.stateManager.SetProfile(cfg.ActiveProfile(), cfg.ActiveTimer())
+	if m.currentPage != nil {
+		m.AddPageAndSwitch(m.currentPage)
+	}
+	m.showNotice(m.theme.Highlight.Wrap("Profile: " + tview.Escape(next)))
+}
+
+// adjustKey adds or removes minutes of timer if event is one of adjust keys.
+func (m *UIManager) adjustKey(timerType TimerType, event *tcell.EventKey) bool {
+	adjustments := map[Action]time.Duration{
+		actionAddMinute:    time.Minute,
+		actionAddFive:      5 * time.Minute,
+		actionRemoveMinute: -time.Minute,
+	}
+	for action, d := range adjustments {
+		if m.keys.Match(action, event) {
+			m.adjustTimer(timerType, d)
+			return true
+		}
+	}
+	return false
+}
+
+// adjustTimer changes remaining time of timer, the change is saved with the session.
+func (m *UIManager) adjustTimer(timerType TimerType, d time.Duration) {
+	applied := m.stateManager.AdjustTimer(timerType, d)
+	if m.currentPage != nil {
+		m.AddPageAndSwitch(m.currentPage)
+	}
+
+	if applied == 0 {
+		m.showNotice(m.theme.Muted.Wrap("Timer can't be shorter"))
+		return
+	}
+	sign := "+"
+	if applied < 0 {
+		sign, applied = "-", -applied
+	}
+	m.showNotice(m.theme.Highlight.Wrap(fmt.Sprintf("Timer %s%s", sign, formatConfigDuration(applied))))
+}
+
+// changeState asks state manager for new state and shows why it's refused, e.g. in strict mode.
+func (m *UIManager) changeState(state TimerState, timerType TimerType) {
+	if err := m.stateManager.SetState(state, timerType); err != nil {
+		m.showNotice(m.theme.Danger.Wrap(err.Error()))
+	}
+}
+
 func (m *UIManager) renderActivePage(args ...any) func() tview.Primitive {
-	color, ok := args[0].(string)
+	style, ok := args[0].(themeStyle)
 	if !ok {
 		m.logger.Error("can't extract arg", slog.String("func", "renderActivePage"),
-			slog.String("expected", "color (string)"))
+			slog.String("expected", "style (themeStyle)"))
 		return nil
 	}
 	title, ok := args[1].(string)
@@ -162,54 +282,51 @@ func (m *UIManager) renderActivePage(args ...any) func() tview.Primitive {
 	}
 
 	return func() tview.Primitive {
-		breakText := tview.NewTextView().
-			SetDynamicColors(true).
-			SetTextAlign(tview.AlignCenter).
-			SetText(fmt.Sprintf("[%s]%s", color, title))
-
-		timerText := tview.NewTextView().
-			SetDynamicColors(true).
-			SetTextAlign(tview.AlignCenter).
+		countdown := tview.NewBox().
 			SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
-				tview.Print(screen, fmt.Sprintf("[%s]%s[-]", color,
-					formatDuration(m.stateManager.timeToFinish(timerType))),
-					x, y+height/4, width, tview.AlignCenter, tcell.ColorLime)
-				return 0, 0, 0, 0
-			})
-
-		hiddenTimerText := tview.NewTextView().
-			SetDynamicColors(true).
-			SetTextAlign(tview.AlignCenter).
-			SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
-				tview.Print(screen, fmt.Sprintf("[%s]%s[-]", color,
-					"focusing"),
-					x, y+height/4, width, tview.AlignCenter, tcell.ColorLime)
-				return 0, 0, 0, 0
+				m.drawCountdown(screen, x, y, width, height, style, m.withPlanStatus(title), timerType)
+				return x, y, width, height
 			})
 
 		pauseButton := tview.NewButton("Pause").SetSelectedFunc(func() {
-			m.stateManager.SetState(StatePaused, timerType)
+			m.changeState(StatePaused, timerType)
 		})
+		// focus can't be paused in strict mode, only abandoned
+		if timerType == FocusTimer && m.stateManager.Strict() {
+			pauseButton.SetLabel("Abandon").SetSelectedFunc(func() {
+				m.changeState(StateFinished, timerType)
+			})
+		}
 
 		toggleButton := tview.NewButton("→").SetSelectedFunc(func() {
-			m.stateManager.SetState(StateFinished, timerType)
+			m.changeState(StateFinished, timerType)
 		})
 
+		// countdown takes all free space to scale big digits
 		grid := tview.NewGrid().
-			SetRows(0, 1, 3, 1, 0, 1).
+			SetRows(0, 1, 1).
 			SetColumns(0, 10, 5, 0).
 			SetBorders(true)
 
-		grid.AddItem(breakText, 1, 1, 1, 2, 0, 0, false)
-		if m.stateManager.IsFocusTimeHidden() && timerType == FocusTimer {
-			grid.AddItem(hiddenTimerText, 2, 1, 1, 2, 0, 0, false)
-		} else {
-			grid.AddItem(timerText, 2, 1, 1, 2, 0, 0, false)
-		}
-		grid.AddItem(pauseButton, 3, 1, 1, 1, 0, 0, true)
-		grid.AddItem(toggleButton, 3, 2, 1, 1, 0, 0, false)
+		grid.AddItem(countdown, 0, 0, 1, 4, 0, 0, false)
+		grid.AddItem(pauseButton, 1, 1, 1, 1, 0, 0, true)
+		grid.AddItem(toggleButton, 1, 2, 1, 1, 0, 0, false)
 
 		grid.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch {
+			case m.keys.Match(actionStartPause, event):
+				m.changeState(StatePaused, timerType)
+				return nil
+			case m.keys.Match(actionSkip, event):
+				m.changeState(StateFinished, timerType)
+				return nil
+			case m.keys.Match(actionAbortPlan, event):
+				m.abortPlan()
+				return nil
+			case m.adjustKey(timerType, event):
+				return nil
+			}
+
 			switch event.Key() {
 			case tcell.KeyTAB, tcell.KeyLeft, tcell.KeyRight:
 				if m.ui.GetFocus() == pauseButton {
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+var errEmptyPlan = errors.New("plan has no intervals")
+
+// planInterval is one timer run of a plan.
+type planInterval struct {
+	timerType TimerType
+	duration  time.Duration
+	name      string
+}
+
+func (i planInterval) String() string {
+	name := i.name
+	if name == "" {
+		name = i.timerType.String()
+	}
+	return fmt.Sprintf("%s %s", name, formatConfigDuration(i.duration))
+}
+
+// planIntervals unrolls plan steps into timer runs.
+func planIntervals(plan config.Plan) []planInterval {
+	intervals := make([]planInterval, 0)
+	for _, step := range plan.Steps {
+		for range step.Times() {
+			if step.Focus > 0 {
+				intervals = append(intervals, planInterval{timerType: FocusTimer, duration: step.Focus, name: step.Name})
+			}
+			if step.Break > 0 {
+				intervals = append(intervals, planInterval{timerType: BreakTimer, duration: step.Break, name: step.Name})
+			}
+		}
+	}
+	return intervals
+}
+
+// PlanRunner moves StateManager through intervals of a session plan: every time current
+// interval finishes, the next one is started.
+type PlanRunner struct {
+	mu           sync.RWMutex
+	stateManager *StateManager
+	name         string
+	intervals    []planInterval
+	current      int
+	// started tells if current interval was run, finish of other sessions doesn't move the plan
+	started bool
+}
+
+func NewPlanRunner(sm *StateManager) *PlanRunner {
+	return &PlanRunner{
+		mu:           sync.RWMutex{},
+		stateManager: sm,
+		name:         "",
+		intervals:    nil,
+		current:      0,
+		started:      false,
+	}
+}
+
+// Start runs the first interval of plan, previous plan is dropped.
+func (r *PlanRunner) Start(plan config.Plan) error {
+	intervals := planIntervals(plan)
+	if len(intervals) == 0 {
+		return fmt.Errorf("%w: %s", errEmptyPlan, plan.Name)
+	}
+
+	r.mu.Lock()
+	r.name = plan.Name
+	r.intervals = intervals
+	r.current = 0
+	r.started = false
+	r.mu.Unlock()
+
+	// active page shows plan status, so plan is set before its first interval starts
+	if err := r.stateManager.StartInterval(intervals[0].timerType, intervals[0].duration); err != nil {
+		r.Abort()
+		return err
+	}
+	return nil
+}
+
+// Abort stops following the plan, running interval goes on as a usual timer.
+func (r *PlanRunner) Abort() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.name = ""
+	r.intervals = nil
+	r.current = 0
+	r.started = false
+}
+
+// HandleStateEvent starts next interval when current one is finished (or skipped).
+// Must not block: it's called by the reader of state events.
+func (r *PlanRunner) HandleStateEvent(event StateEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.intervals == nil || r.intervals[r.current].timerType != event.TimerType {
+		return
+	}
+
+	switch event.NewState {
+	case StateActive:
+		r.started = true
+		return
+	case StateFinished:
+		if !r.started {
+			return
+		}
+	default:
+		return
+	}
+
+	r.current++
+	r.started = false
+	if r.current >= len(r.intervals) {
+		r.name = ""
+		r.intervals = nil
+		r.current = 0
+		return
+	}
+
+	next := r.intervals[r.current]
+	go func() {
+		// previous interval is finished, nothing is running that strict mode would keep
+		_ = r.stateManager.StartInterval(next.timerType, next.duration)
+	}()
+}
+
+// planStatus describes running plan for timer pages.
+type planStatus struct {
+	name    string
+	step    int
+	steps   int
+	current planInterval
+	next    *planInterval
+}
+
+// Status returns state of running plan, false if there is no plan.
+func (r *PlanRunner) Status() (planStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.intervals == nil {
+		return planStatus{}, false
+	}
+
+	status := planStatus{
+		name:    r.name,
+		step:    r.current + 1,
+		steps:   len(r.intervals),
+		current: r.intervals[r.current],
+		next:    nil,
+	}
+	if r.current+1 < len(r.intervals) {
+		next := r.intervals[r.current+1]
+		status.next = &next
+	}
+	return status, true
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanIntervals(t *testing.T) {
+	plan := config.Plan{Name: "exam prep", Steps: []config.PlanStep{
+		{Focus: 50 * time.Minute, Break: 10 * time.Minute, Repeat: 3},
+		{Name: "lunch", Break: 30 * time.Minute},
+		{Focus: 25 * time.Minute, Break: 5 * time.Minute, Repeat: 2},
+	}}
+
+	intervals := planIntervals(plan)
+	require.Len(t, intervals, 11)
+	assert.Equal(t, planInterval{timerType: FocusTimer, duration: 50 * time.Minute, name: ""}, intervals[0])
+	assert.Equal(t, planInterval{timerType: BreakTimer, duration: 10 * time.Minute, name: ""}, intervals[5])
+	assert.Equal(t, "lunch 30m", intervals[6].String())
+	assert.Equal(t, "break 5m", intervals[10].String())
+}
+
+func TestPlanRunner(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 1)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, HiddenFocusTime: false}, s)
+	runner := NewPlanRunner(stateManager)
+
+	plan := config.Plan{Name: "short", Steps: []config.PlanStep{{Focus: time.Minute, Break: 2 * time.Minute}}}
+	require.NoError(t, runner.Start(plan))
+	runner.HandleStateEvent(<-stateChan)
+
+	status, ok := runner.Status()
+	require.True(t, ok)
+	assert.Equal(t, 1, status.step)
+	assert.Equal(t, 2, status.steps)
+	assert.Equal(t, "break 2m", status.next.String())
+	assert.Equal(t, time.Minute, stateManager.duration(FocusTimer))
+
+	// finished focus starts break of the plan
+	stateManager.SetState(StateFinished, FocusTimer)
+	runner.HandleStateEvent(<-stateChan)
+	event := <-stateChan
+	assert.Equal(t, BreakTimer, event.TimerType)
+	assert.Equal(t, StateActive, event.NewState)
+	runner.HandleStateEvent(event)
+	assert.Equal(t, config.DefaultProfile, event.Profile)
+	assert.Equal(t, 2*time.Minute, stateManager.duration(BreakTimer))
+	assert.Equal(t, focusDuration, stateManager.duration(FocusTimer))
+
+	status, ok = runner.Status()
+	require.True(t, ok)
+	assert.Equal(t, 2, status.step)
+	assert.Nil(t, status.next)
+
+	// last interval finishes the plan, timer gets length from config
+	stateManager.SetState(StateFinished, BreakTimer)
+	runner.HandleStateEvent(<-stateChan)
+	_, ok = runner.Status()
+	assert.False(t, ok)
+	assert.Equal(t, breakDuration, breakTimer.TimeToFinish())
+}
+
+func TestPlanRunner_FinishesPaused(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 4)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration}, s)
+	runner := NewPlanRunner(stateManager)
+
+	require.NoError(t, stateManager.SetState(StateActive, FocusTimer))
+	require.NoError(t, stateManager.SetState(StatePaused, FocusTimer))
+	<-stateChan
+	<-stateChan
+
+	// paused focus is finished before plan starts, its finish doesn't move the plan
+	plan := config.Plan{Name: "short", Steps: []config.PlanStep{{Focus: time.Minute, Break: 2 * time.Minute}}}
+	require.NoError(t, runner.Start(plan))
+	event := <-stateChan
+	assert.Equal(t, FocusTimer, event.TimerType)
+	assert.Equal(t, StateFinished, event.NewState)
+	assert.True(t, event.Skipped)
+	runner.HandleStateEvent(event)
+
+	event = <-stateChan
+	assert.Equal(t, FocusTimer, event.TimerType)
+	assert.Equal(t, StateActive, event.NewState)
+	runner.HandleStateEvent(event)
+	assert.Equal(t, time.Minute, focusTimer.TimeToFinish().Round(time.Minute))
+
+	status, ok := runner.Status()
+	require.True(t, ok)
+	assert.Equal(t, 1, status.step)
+
+	focusTimer.Stop()
+}
@@ -1,27 +1,107 @@
 package main
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync"
 	"time"
+
+	"github.com/arevbond/PomoTrack/config"
 )
 
+var errNoFinishedPomodoro = errors.New("no finished pomodoro")
+
 type Pomodoro struct {
+	ID              int       `db:"id"        json:"id"`
+	StartAt         time.Time `db:"start_at"  json:"start_at"`
+	FinishAt        time.Time `db:"finish_at" json:"finish_at"`
+	SecondsDuration int       `db:"duration"  json:"duration"`
+	Profile         string    `db:"profile"   json:"profile"`
+	// AdjustedSeconds is time added to the session by hand, negative if it was shortened.
+	AdjustedSeconds int `db:"adjusted"  json:"adjusted"`
+	// OvertimeSeconds is part of duration counted past zero in flow mode.
+	OvertimeSeconds int            `db:"overtime"  json:"overtime"`
+	Kind            SessionKind    `db:"kind"      json:"kind"`
+	Outcome         SessionOutcome `db:"outcome"   json:"outcome"`
+	// Task is the name of task active when focus started.
+	Task string `db:"task" json:"task"`
+	// GitRepo and GitBranch are root and branch of git repository PomoTrack was started in, when focus started.
+	GitRepo   string `db:"git_repo"   json:"git_repo"`
+	GitBranch string `db:"git_branch" json:"git_branch"`
+	// Pauses and PausedSeconds are counted from segments of session.
+	Pauses        int `json:"pauses"`
+	PausedSeconds int `json:"paused"`
+
+	// don't save to db; need for app logic
+	lastStartAt time.Time
+	running     bool
+	finished    bool
+}
+
+// Segment is time session was running between two pauses.
+type Segment struct {
 	ID              int       `db:"id"`
+	PomodoroID      int       `db:"pomodoro_id"`
 	StartAt         time.Time `db:"start_at"`
 	FinishAt        time.Time `db:"finish_at"`
 	SecondsDuration int       `db:"duration"`
+}
 
-	// don't save to db; need for app logic
-	lastStartAt time.Time
-	finished    bool
+// SessionKind tells what time session measures.
+type SessionKind string
+
+const (
+	SessionFocus SessionKind = "focus"
+	SessionBreak SessionKind = "break"
+)
+
+// SessionOutcome tells how session ended. Session is stored as abandoned
+// until it finishes, so sessions lost on crash stay abandoned.
+type SessionOutcome string
+
+const (
+	OutcomeCompleted SessionOutcome = "completed"
+	OutcomeSkipped   SessionOutcome = "skipped"
+	OutcomeAbandoned SessionOutcome = "abandoned"
+	// OutcomeVoided is focus paused over the limit, it doesn't count in hours.
+	OutcomeVoided SessionOutcome = "voided"
+	// OutcomeIncomplete is focus shorter than configured minimum, it doesn't count in hours.
+	OutcomeIncomplete SessionOutcome = "incomplete"
+)
+
+// counted tells if session goes to statistics hours.
+func (p *Pomodoro) counted() bool {
+	return p.Outcome != OutcomeVoided && p.Outcome != OutcomeIncomplete
+}
+
+// SuspendEvent tells that focus session ran out while computer was asleep.
+type SuspendEvent struct {
+	SessionID int
+	// At is the moment session ran out.
+	At     time.Time
+	Asleep time.Duration
 }
 
 type PomodoroManager struct {
 	storage           *Storage
 	logger            *slog.Logger
-	currentPomodoro   *Pomodoro
 	statePomodoroChan chan StateEvent
+
+	// sessionMu guards current sessions, state events are handled under it
+	sessionMu       sync.Mutex
+	currentPomodoro *Pomodoro
+	currentBreak    *Pomodoro
+
+	mu           sync.RWMutex
+	pauseLimit   config.PausesConfig
+	discardShort bool
+	onFinish     func(*Pomodoro)
+	onSuspend    func(SuspendEvent)
+	tasks        taskManager
+	workDir      string
 }
 
 func NewPomodoroManager(logger *slog.Logger, storage *Storage, stateEvents chan StateEvent) *PomodoroManager {
@@ -29,23 +109,110 @@ func NewPomodoroManager(logger *slog.Logger, storage *Storage, stateEvents chan
 		storage:           storage,
 		logger:            logger,
 		statePomodoroChan: stateEvents,
+		sessionMu:         sync.Mutex{},
 		currentPomodoro:   nil,
+		currentBreak:      nil,
+		mu:                sync.RWMutex{},
+		pauseLimit:        config.PausesConfig{},
+		discardShort:      false,
+		onFinish:          nil,
+		onSuspend:         nil,
+		tasks:             storage,
+		workDir:           "",
 	}
 }
 
+// SetWorkDir sets directory whose git repository and branch are saved with focus sessions.
+func (tm *PomodoroManager) SetWorkDir(dir string) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.workDir = dir
+}
+
+// SetTaskSource sets where name of active task is taken for new pomodoros.
+func (tm *PomodoroManager) SetTaskSource(tasks taskManager) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.tasks = tasks
+}
+
+// OnFinish sets function called after focus session is finished and stored.
+func (tm *PomodoroManager) OnFinish(f func(*Pomodoro)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.onFinish = f
+}
+
+// OnSuspend sets function called after focus session that ran out during suspend is finished.
+func (tm *PomodoroManager) OnSuspend(f func(SuspendEvent)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.onSuspend = f
+}
+
+// SetDiscardShort makes focus sessions shorter than minimum deleted instead of stored as incomplete.
+func (tm *PomodoroManager) SetDiscardShort(discard bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.discardShort = discard
+}
+
+// SetPauseLimit sets limit of pauses in focus session, it's checked when session finishes.
+func (tm *PomodoroManager) SetPauseLimit(limit config.PausesConfig) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	tm.pauseLimit = limit
+}
+
+// PauseLimit returns limit of pauses in focus session.
+func (tm *PomodoroManager) PauseLimit() config.PausesConfig {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.pauseLimit
+}
+
 func (tm *PomodoroManager) HandlePomodoroStateChanges() {
 	for event := range tm.statePomodoroChan {
-		if event.TimerType == FocusTimer {
-			switch event.NewState {
-			case StateActive:
-				tm.handleStartPomodoro()
-			case StatePaused:
-				tm.handlePausePomodoro()
-			case StateFinished:
-				tm.handleFinishPomodoro()
-			}
+		session := tm.handleStateEvent(event)
+
+		tm.mu.RLock()
+		onSuspend := tm.onSuspend
+		tm.mu.RUnlock()
+		if onSuspend != nil && session != nil && event.NewState == StateFinished && event.TimerType.IsFocus() &&
+			event.Asleep > 0 {
+			onSuspend(SuspendEvent{SessionID: session.ID, At: event.At, Asleep: event.Asleep})
+		}
+	}
+}
+
+// handleStateEvent applies event to current session of its timer and returns the session.
+func (tm *PomodoroManager) handleStateEvent(event StateEvent) *Pomodoro {
+	tm.sessionMu.Lock()
+	defer tm.sessionMu.Unlock()
+
+	current, kind := &tm.currentPomodoro, SessionFocus
+	if !event.TimerType.IsFocus() {
+		current, kind = &tm.currentBreak, SessionBreak
+	}
+
+	switch event.NewState {
+	case StateActive:
+		tm.handleStartPomodoro(current, kind, event.Profile, event.At)
+	case StatePaused:
+		tm.handlePausePomodoro(*current, event.At, event.Adjusted)
+	case StateFinished:
+		outcome := OutcomeCompleted
+		switch {
+		case event.TooShort:
+			outcome = OutcomeIncomplete
+		case event.Skipped:
+			outcome = OutcomeSkipped
+		case event.Abandoned:
+			outcome = OutcomeAbandoned
 		}
+		tm.handleFinishPomodoro(*current, event.At, event.Adjusted, event.Overtime, outcome)
 	}
+	return *current
 }
 
 func (tm *PomodoroManager) Pomodoros() ([]*Pomodoro, error) {
@@ -56,17 +223,62 @@ func (tm *PomodoroManager) TodayPomodoros() ([]*Pomodoro, error) {
 	return tm.storage.GetTodayPomodoros()
 }
 
+// Breaks returns stored break sessions.
+func (tm *PomodoroManager) Breaks() ([]*Pomodoro, error) {
+	return tm.storage.GetBreaks()
+}
+
+func (tm *PomodoroManager) PomodorosBetween(from, to time.Time) ([]*Pomodoro, error) {
+	return tm.storage.GetPomodorosBetween(from, to)
+}
+
+// ExportCalendar writes sessions to .ics file and returns its path.
+func (tm *PomodoroManager) ExportCalendar() (string, error) {
+	return exportCalendar(tm.storage)
+}
+
 func (tm *PomodoroManager) RemovePomodoro(id int) error {
 	return tm.storage.RemovePomodoro(id)
 }
 
 func (tm *PomodoroManager) CreateNewPomodoro(startAt time.Time, finishAt time.Time, duration int) (*Pomodoro, error) {
+	return tm.createPomodoro(startAt, finishAt, duration, "", SessionFocus, OutcomeCompleted)
+}
+
+func (tm *PomodoroManager) createPomodoro(startAt time.Time, finishAt time.Time, duration int,
+	profile string, kind SessionKind, outcome SessionOutcome) (*Pomodoro, error) {
+	var task, repo, branch string
+	if kind == SessionFocus {
+		tm.mu.RLock()
+		tasks, workDir := tm.tasks, tm.workDir
+		tm.mu.RUnlock()
+		name, err := tasks.ActiveTaskName()
+		if err != nil {
+			tm.logger.Error("can't get active task of pomodoro", slog.Any("error", err))
+		}
+		task = name
+		if workDir != "" {
+			repo, branch = gitHead(workDir)
+		}
+	}
+
 	pomodoro := &Pomodoro{
 		ID:              0,
 		StartAt:         startAt,
 		FinishAt:        finishAt,
 		SecondsDuration: duration,
+		Profile:         profile,
+		AdjustedSeconds: 0,
+		OvertimeSeconds: 0,
+		Kind:            kind,
+		Outcome:         outcome,
+		Task:            task,
+		GitRepo:         repo,
+		GitBranch:       branch,
+		Pauses:          0,
+		PausedSeconds:   0,
 		lastStartAt:     time.Now(),
+		running:         false,
 		finished:        false,
 	}
 
@@ -80,24 +292,129 @@ func (tm *PomodoroManager) CreateNewPomodoro(startAt time.Time, finishAt time.Ti
 func (tm *PomodoroManager) Hours(pomodoros []*Pomodoro) float64 {
 	var result time.Duration
 	for _, pomodoro := range pomodoros {
+		if !pomodoro.counted() {
+			continue
+		}
 		result += time.Duration(pomodoro.SecondsDuration) * time.Second
 	}
 	return result.Hours()
 }
 
-func (tm *PomodoroManager) CountDays(pomodoros []*Pomodoro) int {
+// OvertimeHours returns time focused past zero in flow mode.
+func (tm *PomodoroManager) OvertimeHours(pomodoros []*Pomodoro) float64 {
+	var result time.Duration
+	for _, pomodoro := range pomodoros {
+		if !pomodoro.counted() {
+			continue
+		}
+		result += time.Duration(pomodoro.OvertimeSeconds) * time.Second
+	}
+	return result.Hours()
+}
+
+// CompletionRate returns share of sessions that ran till the end, from 0 to 1.
+func (tm *PomodoroManager) CompletionRate(pomodoros []*Pomodoro) float64 {
 	if len(pomodoros) == 0 {
 		return 0
 	}
 
-	count := 1
-	for i := 1; i < len(pomodoros); i++ {
-		prev := pomodoros[i-1]
-		cur := pomodoros[i]
+	var completed int
+	for _, pomodoro := range pomodoros {
+		if pomodoro.Outcome == OutcomeCompleted {
+			completed++
+		}
+	}
+	return float64(completed) / float64(len(pomodoros))
+}
+
+// ProfileHours is time focused with timer profile.
+type ProfileHours struct {
+	Profile string
+	Hours   float64
+}
+
+// HoursByProfile returns time focused with each timer profile, the most focused first.
+// Sessions recorded before profiles and records added by hand belong to default profile.
+func (tm *PomodoroManager) HoursByProfile(pomodoros []*Pomodoro) []ProfileHours {
+	profiles := make(map[string]time.Duration)
+	for _, pomodoro := range pomodoros {
+		if !pomodoro.counted() {
+			continue
+		}
+		profile := pomodoro.Profile
+		if profile == "" {
+			profile = config.DefaultProfile
+		}
+		profiles[profile] += time.Duration(pomodoro.SecondsDuration) * time.Second
+	}
+
+	result := make([]ProfileHours, 0, len(profiles))
+	for profile, duration := range profiles {
+		result = append(result, ProfileHours{Profile: profile, Hours: duration.Hours()})
+	}
+	slices.SortFunc(result, func(a, b ProfileHours) int {
+		return cmp.Or(cmp.Compare(b.Hours, a.Hours), cmp.Compare(a.Profile, b.Profile))
+	})
+	return result
+}
+
+// RepoHours is time focused in git repository, in total and by branch.
+type RepoHours struct {
+	Repo     string
+	Hours    float64
+	Branches []BranchHours
+}
+
+type BranchHours struct {
+	Branch string
+	Hours  float64
+}
+
+// HoursByRepo returns time focused in each git repository, the most focused first.
+// Sessions started outside of repository aren't counted.
+func (tm *PomodoroManager) HoursByRepo(pomodoros []*Pomodoro) []RepoHours {
+	repos := make(map[string]map[string]time.Duration)
+	for _, pomodoro := range pomodoros {
+		if !pomodoro.counted() || pomodoro.GitRepo == "" {
+			continue
+		}
+		if repos[pomodoro.GitRepo] == nil {
+			repos[pomodoro.GitRepo] = make(map[string]time.Duration)
+		}
+		repos[pomodoro.GitRepo][pomodoro.GitBranch] += time.Duration(pomodoro.SecondsDuration) * time.Second
+	}
+
+	result := make([]RepoHours, 0, len(repos))
+	for repo, branches := range repos {
+		repoHours := RepoHours{Repo: repo, Hours: 0, Branches: make([]BranchHours, 0, len(branches))}
+		for branch, duration := range branches {
+			repoHours.Hours += duration.Hours()
+			repoHours.Branches = append(repoHours.Branches, BranchHours{Branch: branch, Hours: duration.Hours()})
+		}
+		slices.SortFunc(repoHours.Branches, func(a, b BranchHours) int {
+			return cmp.Or(cmp.Compare(b.Hours, a.Hours), cmp.Compare(a.Branch, b.Branch))
+		})
+		result = append(result, repoHours)
+	}
+	slices.SortFunc(result, func(a, b RepoHours) int {
+		return cmp.Or(cmp.Compare(b.Hours, a.Hours), cmp.Compare(a.Repo, b.Repo))
+	})
+	return result
+}
 
-		if prev.StartAt.Day() != cur.StartAt.Day() {
+func (tm *PomodoroManager) CountDays(pomodoros []*Pomodoro) int {
+	var (
+		count int
+		prev  *Pomodoro
+	)
+	for _, cur := range pomodoros {
+		if !cur.counted() {
+			continue
+		}
+		if prev == nil || prev.StartAt.Day() != cur.StartAt.Day() {
 			count++
 		}
+		prev = cur
 	}
 
 	return count
@@ -122,6 +439,9 @@ func (tm *PomodoroManager) HoursInWeek(pomodoros []*Pomodoro) [7]int {
 	weekEnd := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
 
 	for _, pomodoro := range pomodoros {
+		if !pomodoro.counted() {
+			continue
+		}
 		// Проверяем, попадает ли в текущую неделю
 		if (pomodoro.StartAt.After(weekStart) || pomodoro.StartAt.Equal(weekStart)) &&
 			(pomodoro.StartAt.Before(weekEnd) || pomodoro.StartAt.Equal(weekEnd)) {
@@ -134,50 +454,143 @@ func (tm *PomodoroManager) HoursInWeek(pomodoros []*Pomodoro) [7]int {
 	return weekdayHours
 }
 
-func (tm *PomodoroManager) FinishRunningPomodoro() {
-	if tm.currentPomodoro != nil && !tm.currentPomodoro.finished {
-		tm.handleFinishPomodoro()
+// FinishRunningPomodoro saves sessions left running on exit as abandoned,
+// focus shorter than minFocus is incomplete.
+func (tm *PomodoroManager) FinishRunningPomodoro(minFocus time.Duration) {
+	tm.sessionMu.Lock()
+	defer tm.sessionMu.Unlock()
+
+	for _, session := range []*Pomodoro{tm.currentPomodoro, tm.currentBreak} {
+		if session == nil || session.finished {
+			continue
+		}
+
+		outcome := OutcomeAbandoned
+		focused := time.Duration(session.SecondsDuration) * time.Second
+		if session.running {
+			focused += time.Since(session.lastStartAt)
+		}
+		if session.Kind == SessionFocus && focused < minFocus {
+			outcome = OutcomeIncomplete
+		}
+		tm.handleFinishPomodoro(session, time.Now(), time.Duration(session.AdjustedSeconds)*time.Second,
+			time.Duration(session.OvertimeSeconds)*time.Second, outcome)
+	}
+}
+
+// SubtractFromPomodoro removes time from finished session, e.g. time computer was asleep during it.
+func (tm *PomodoroManager) SubtractFromPomodoro(id int, d time.Duration) error {
+	tm.sessionMu.Lock()
+	defer tm.sessionMu.Unlock()
+
+	seconds := int(d.Seconds())
+	if err := tm.storage.SubtractFromPomodoro(id, seconds); err != nil {
+		return err
 	}
+	if session := tm.currentPomodoro; session != nil && session.ID == id {
+		session.SecondsDuration = max(session.SecondsDuration-seconds, 0)
+	}
+	return nil
 }
 
-func (tm *PomodoroManager) handleStartPomodoro() {
+func (tm *PomodoroManager) handleStartPomodoro(current **Pomodoro, kind SessionKind, profile string, at time.Time) {
 	// предыдущая задача не создана или завершена
 	// создаём новую пустую задачу
-	if tm.currentPomodoro == nil || tm.currentPomodoro.finished {
-		newPomodoro, err := tm.CreateNewPomodoro(time.Now(), time.Now(), 0)
+	if *current == nil || (*current).finished {
+		newPomodoro, err := tm.createPomodoro(at, at, 0, profile, kind, OutcomeAbandoned)
 		if err != nil {
 			tm.logger.Error("handle start pomodoro", slog.Any("error", err))
 		}
-		tm.currentPomodoro = newPomodoro
+		if newPomodoro != nil {
+			newPomodoro.lastStartAt = at
+			newPomodoro.running = true
+		}
+		*current = newPomodoro
 		return
 	}
 
 	// есть текущая незавершённая задача (запуск после паузы)
-	tm.currentPomodoro.lastStartAt = time.Now()
+	session := *current
+	if !session.running {
+		session.Pauses++
+		session.PausedSeconds += int(at.Sub(session.FinishAt).Seconds())
+	}
+	session.lastStartAt = at
+	session.running = true
 }
 
-func (tm *PomodoroManager) handlePausePomodoro() {
-	err := tm.updateCurrentPomodoroDuration()
+func (tm *PomodoroManager) handlePausePomodoro(session *Pomodoro, at time.Time, adjusted time.Duration) {
+	if session == nil || session.finished {
+		return
+	}
+	err := tm.updateSessionDuration(session, at, adjusted)
 	if err != nil {
-		tm.logger.Error("handle start pomodoro", slog.Any("error", err))
+		tm.logger.Error("handle pause pomodoro", slog.Any("error", err))
 	}
 }
 
-func (tm *PomodoroManager) handleFinishPomodoro() {
-	tm.currentPomodoro.finished = true
-	err := tm.updateCurrentPomodoroDuration()
+func (tm *PomodoroManager) handleFinishPomodoro(session *Pomodoro, at time.Time, adjusted, overtime time.Duration,
+	outcome SessionOutcome) {
+	if session == nil || session.finished {
+		return
+	}
+	session.finished = true
+	session.OvertimeSeconds = int(overtime.Seconds())
+	session.Outcome = outcome
+	if limit := tm.PauseLimit(); session.Kind == SessionFocus && outcome != OutcomeIncomplete && limit.Void &&
+		limit.Exceeded(session.Pauses, time.Duration(session.PausedSeconds)*time.Second) {
+		session.Outcome = OutcomeVoided
+	}
+	err := tm.updateSessionDuration(session, at, adjusted)
 	if err != nil {
-		tm.logger.Error("handle start pomodoro", slog.Any("error", err))
+		tm.logger.Error("handle finish pomodoro", slog.Any("error", err))
+	}
+
+	if session.Outcome == OutcomeIncomplete && tm.discardingShort() {
+		if err = tm.storage.RemovePomodoro(session.ID); err != nil {
+			tm.logger.Error("discard short pomodoro", slog.Any("error", err))
+		}
+		return
 	}
+
+	tm.mu.RLock()
+	onFinish := tm.onFinish
+	tm.mu.RUnlock()
+	if onFinish != nil && session.Kind == SessionFocus {
+		onFinish(session)
+	}
+}
+
+func (tm *PomodoroManager) discardingShort() bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+	return tm.discardShort
 }
 
-func (tm *PomodoroManager) updateCurrentPomodoroDuration() error {
-	duration := int(time.Since(tm.currentPomodoro.lastStartAt).Seconds())
+// updateSessionDuration saves segment from last start till moment at if session was running
+// and total time the timer was adjusted by.
+func (tm *PomodoroManager) updateSessionDuration(session *Pomodoro, at time.Time, adjusted time.Duration) error {
+	session.AdjustedSeconds = int(adjusted.Seconds())
+
+	if session.running {
+		duration := int(at.Sub(session.lastStartAt).Seconds())
+		session.running = false
+		session.SecondsDuration += duration
+		session.FinishAt = at
 
-	tm.currentPomodoro.SecondsDuration += duration
-	tm.currentPomodoro.FinishAt = time.Now()
+		err := tm.storage.CreateSegment(&Segment{
+			ID:              0,
+			PomodoroID:      session.ID,
+			StartAt:         session.lastStartAt,
+			FinishAt:        at,
+			SecondsDuration: duration,
+		})
+		if err != nil {
+			return fmt.Errorf("can't save segment: %w", err)
+		}
+	}
 
-	err := tm.storage.UpdatePomodoro(tm.currentPomodoro)
+	err := tm.storage.UpdatePomodoro(session)
 	if err != nil {
 		return fmt.Errorf("can't update pomdooro: %w", err)
 	}
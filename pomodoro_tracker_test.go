@@ -0,0 +1,26 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPomodoroManager_SkipsNotCounted(t *testing.T) {
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)
+	pomodoros := []*Pomodoro{
+		{StartAt: start.Add(-24 * time.Hour), Outcome: OutcomeVoided, OvertimeSeconds: 60},
+		{StartAt: start, Outcome: OutcomeCompleted, OvertimeSeconds: 5 * 60},
+		{StartAt: start.Add(time.Hour), Outcome: OutcomeIncomplete, OvertimeSeconds: 60},
+		{StartAt: start.Add(2 * time.Hour), Outcome: OutcomeSkipped},
+		{StartAt: start.Add(24 * time.Hour), Outcome: OutcomeIncomplete},
+	}
+	tm := NewPomodoroManager(slog.Default(), nil, nil)
+
+	assert.Equal(t, 1, tm.CountDays(pomodoros))
+	assert.InDelta(t, (5 * time.Minute).Hours(), tm.OvertimeHours(pomodoros), 1e-9)
+	assert.Equal(t, 0, tm.CountDays(pomodoros[:1]))
+}
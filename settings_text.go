@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+// Settings page edits lists of config in one line: plan steps like "50m/10m x3, lunch=/30m",
+// flowtime rules like "25m:5m, 50m:10m" and sync projects like "report=123, book=456".
+// Names keep separators escaped with backslash, e.g. "Review\, part 2=/10m".
+
+var errSettingsFormat = errors.New("invalid format")
+
+const listSeparator = ","
+
+// formatPlanSteps prints steps as "[name=]focus/break[ xRepeat]", omitted duration is left empty.
+func formatPlanSteps(steps []config.PlanStep) string {
+	parts := make([]string, 0, len(steps))
+	for _, step := range steps {
+		var sb strings.Builder
+		if step.Name != "" {
+			sb.WriteString(escapeName(step.Name) + "=")
+		}
+		sb.WriteString(formatOptionalDuration(step.Focus) + "/" + formatOptionalDuration(step.Break))
+		if step.Repeat > 0 {
+			sb.WriteString(" x" + strconv.Itoa(step.Repeat))
+		}
+		parts = append(parts, sb.String())
+	}
+	return strings.Join(parts, listSeparator+" ")
+}
+
+func parsePlanSteps(text string) ([]config.PlanStep, error) {
+	var steps []config.PlanStep
+	for _, part := range splitList(text) {
+		name, rest, ok := cutEscaped(part, '=')
+		if !ok {
+			name, rest = "", part
+		}
+		rest, repeatText, _ := strings.Cut(rest, "x")
+		focusText, breakText, ok := strings.Cut(rest, "/")
+		if !ok {
+			return nil, fmt.Errorf("%w: step %q, expected focus/break", errSettingsFormat, part)
+		}
+
+		focus, err := parseOptionalDuration(focusText)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", part, err)
+		}
+		breakDuration, err := parseOptionalDuration(breakText)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", part, err)
+		}
+		repeat, err := parseOptionalInt(repeatText)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", part, err)
+		}
+		steps = append(steps, config.PlanStep{
+			Name:   unescapeName(strings.TrimSpace(name)),
+			Focus:  focus,
+			Break:  breakDuration,
+			Repeat: repeat,
+		})
+	}
+	return steps, nil
+}
+
+// formatFlowtimeRules prints rules as "up_to:break".
+func formatFlowtimeRules(rules []config.FlowtimeRule) string {
+	parts := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		parts = append(parts, formatConfigDuration(rule.UpTo)+":"+formatConfigDuration(rule.Break))
+	}
+	return strings.Join(parts, listSeparator+" ")
+}
+
+func parseFlowtimeRules(text string) ([]config.FlowtimeRule, error) {
+	var rules []config.FlowtimeRule
+	for _, part := range splitList(text) {
+		upToText, breakText, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: rule %q, expected up_to:break", errSettingsFormat, part)
+		}
+		upTo, err := time.ParseDuration(strings.TrimSpace(upToText))
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", part, err)
+		}
+		breakDuration, err := time.ParseDuration(strings.TrimSpace(breakText))
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", part, err)
+		}
+		rules = append(rules, config.FlowtimeRule{UpTo: upTo, Break: breakDuration})
+	}
+	return rules, nil
+}
+
+// formatProjects prints task projects as "task=project" sorted by task.
+func formatProjects(projects map[string]string) string {
+	tasks := make([]string, 0, len(projects))
+	for task := range projects {
+		tasks = append(tasks, task)
+	}
+	sort.Strings(tasks)
+
+	parts := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		parts = append(parts, escapeName(task)+"="+escapeName(projects[task]))
+	}
+	return strings.Join(parts, listSeparator+" ")
+}
+
+func parseProjects(text string) (map[string]string, error) {
+	var projects map[string]string
+	for _, part := range splitList(text) {
+		task, project, ok := cutEscaped(part, '=')
+		task, project = unescapeName(strings.TrimSpace(task)), unescapeName(strings.TrimSpace(project))
+		if !ok || task == "" || project == "" {
+			return nil, fmt.Errorf("%w: project %q, expected task=project", errSettingsFormat, part)
+		}
+		if projects == nil {
+			projects = make(map[string]string)
+		}
+		projects[task] = project
+	}
+	return projects, nil
+}
+
+// splitList returns non empty items of comma separated list, escaped commas don't split it.
+func splitList(text string) []string {
+	var parts []string
+	for {
+		part, rest, found := cutEscaped(text, listSeparator[0])
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+		if !found {
+			return parts
+		}
+		text = rest
+	}
+}
+
+// cutEscaped slices text around the first sep that isn't escaped, escapes are kept.
+func cutEscaped(text string, sep byte) (string, string, bool) {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case sep:
+			return text[:i], text[i+1:], true
+		}
+	}
+	return text, "", false
+}
+
+var nameEscaper = strings.NewReplacer(`\`, `\\`, listSeparator, `\`+listSeparator, "=", `\=`)
+
+// escapeName escapes separators of lists in name.
+func escapeName(name string) string {
+	return nameEscaper.Replace(name)
+}
+
+// unescapeName drops backslashes that escape the next character.
+func unescapeName(name string) string {
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+1 < len(name) {
+			i++
+		}
+		sb.WriteByte(name[i])
+	}
+	return sb.String()
+}
+
+// formatOptionalDuration prints zero duration as empty field.
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return formatConfigDuration(d)
+}
+
+// parseOptionalDuration reads empty field as zero duration.
+func parseOptionalDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", errSettingsFormat, err)
+	}
+	return d, nil
+}
+
+// formatOptionalInt prints zero as empty field.
+func formatOptionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// parseOptionalInt reads empty field as zero.
+func parseOptionalInt(text string) (int, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q isn't a number", errSettingsFormat, text)
+	}
+	return n, nil
+}
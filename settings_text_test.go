@@ -0,0 +1,80 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanStepsText(t *testing.T) {
+	steps := []config.PlanStep{
+		{Focus: 50 * time.Minute, Break: 10 * time.Minute, Repeat: 3},
+		{Name: "lunch", Break: 30 * time.Minute},
+		{Focus: 90 * time.Minute},
+	}
+	text := formatPlanSteps(steps)
+	assert.Equal(t, "50m/10m x3, lunch=/30m, 1h30m/", text)
+
+	parsed, err := parsePlanSteps(text)
+	require.NoError(t, err)
+	assert.Equal(t, steps, parsed)
+
+	_, err = parsePlanSteps("50m")
+	require.ErrorIs(t, err, errSettingsFormat)
+	_, err = parsePlanSteps("50m/10m xmany")
+	require.ErrorIs(t, err, errSettingsFormat)
+
+	parsed, err = parsePlanSteps(" ")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+}
+
+func TestPlanStepsText_EscapedName(t *testing.T) {
+	steps := []config.PlanStep{{Name: `Review, part 2=a\b`, Break: 10 * time.Minute}, {Focus: time.Hour}}
+	text := formatPlanSteps(steps)
+	assert.Equal(t, `Review\, part 2\=a\\b=/10m, 1h/`, text)
+
+	parsed, err := parsePlanSteps(text)
+	require.NoError(t, err)
+	assert.Equal(t, steps, parsed)
+}
+
+func TestFlowtimeRulesText(t *testing.T) {
+	rules := []config.FlowtimeRule{{UpTo: 25 * time.Minute, Break: 5 * time.Minute}, {UpTo: time.Hour, Break: 15 * time.Minute}}
+	text := formatFlowtimeRules(rules)
+	assert.Equal(t, "25m:5m, 1h:15m", text)
+
+	parsed, err := parseFlowtimeRules(text)
+	require.NoError(t, err)
+	assert.Equal(t, rules, parsed)
+
+	_, err = parseFlowtimeRules("25m")
+	require.ErrorIs(t, err, errSettingsFormat)
+}
+
+func TestProjectsText(t *testing.T) {
+	projects := map[string]string{"write report": "123", "book": "456"}
+	text := formatProjects(projects)
+	assert.Equal(t, "book=456, write report=123", text)
+
+	parsed, err := parseProjects(text)
+	require.NoError(t, err)
+	assert.Equal(t, projects, parsed)
+
+	projects = map[string]string{"Review, part 2": "1=2"}
+	parsed, err = parseProjects(formatProjects(projects))
+	require.NoError(t, err)
+	assert.Equal(t, projects, parsed)
+
+	_, err = parseProjects("book")
+	require.ErrorIs(t, err, errSettingsFormat)
+
+	parsed, err = parseProjects("")
+	require.NoError(t, err)
+	assert.Nil(t, parsed)
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/arevbond/PomoTrack/config"
@@ -13,67 +16,201 @@ const (
 	StatePaused TimerState = iota
 	StateActive
 	StateFinished
+	// StateOvertime is sent when focus timer in flow mode reaches zero and goes on counting,
+	// timer stays active until it's finished by hand.
+	StateOvertime
 )
 
+func (s TimerState) String() string {
+	switch s {
+	case StatePaused:
+		return "paused"
+	case StateActive:
+		return "active"
+	case StateFinished:
+		return "finished"
+	case StateOvertime:
+		return "overtime"
+	}
+	return "unknown"
+}
+
 type StateManager struct {
+	mu           sync.RWMutex
 	currentState TimerState
+	currentTimer TimerType
 	focusTimer   *Timer
 	breakTimer   *Timer
+	stopwatch    *Timer
 	stateChan    chan StateEvent
 	logger       *slog.Logger
 	timerConfig  config.TimerConfig
+	flowtime     config.FlowtimeConfig
+	minimum      config.MinimumConfig
+	profile      string
 	taskManager  taskManager
+	// intervals keeps lengths of running plan steps, they override config until timer finishes
+	intervals map[TimerType]time.Duration
+	// paused keeps timers stopped in the middle of interval
+	paused map[TimerType]bool
 }
 
 func NewStateManager(l *slog.Logger, focusT *Timer, breakT *Timer,
 	stateChan chan StateEvent, cfg config.TimerConfig, manager taskManager) *StateManager {
 	return &StateManager{
 		logger:       l,
+		mu:           sync.RWMutex{},
 		currentState: StatePaused,
+		currentTimer: FocusTimer,
 		focusTimer:   focusT,
 		breakTimer:   breakT,
+		stopwatch:    NewStopwatch(),
 		stateChan:    stateChan,
 		timerConfig:  cfg,
+		flowtime:     config.FlowtimeConfig{Divisor: 0, MinBreak: 0, MaxBreak: 0, Rules: nil},
+		minimum:      config.MinimumConfig{Duration: 0, Percent: 0, Discard: false},
+		profile:      config.DefaultProfile,
 		taskManager:  manager,
+		intervals:    make(map[TimerType]time.Duration),
+		paused:       make(map[TimerType]bool),
 	}
 }
 
 func (sm *StateManager) CurrentState() TimerState {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
 	return sm.currentState
 }
 
-func (sm *StateManager) SetState(state TimerState, timerType TimerType) {
-	if sm.currentState == state {
-		return
+// CurrentTimer returns type of the timer that was changed last.
+func (sm *StateManager) CurrentTimer() TimerType {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.currentTimer
+}
+
+var errStrictMode = errors.New("strict mode")
+
+// SetState changes state by request of user, in strict mode it refuses pauses and skipped breaks.
+func (sm *StateManager) SetState(state TimerState, timerType TimerType) error {
+	if err := sm.checkStrict(state, timerType); err != nil {
+		return err
 	}
+	sm.setState(state, timerType, time.Now(), 0)
+	return nil
+}
 
-	sm.currentState = state
+// checkStrict tells if change is forbidden by strict mode. Running timer can't be left for another one
+// or paused, except focus in overtime where pause finishes it, and break can't be finished early.
+func (sm *StateManager) checkStrict(state TimerState, timerType TimerType) error {
+	if !sm.Strict() {
+		return nil
+	}
 
-	event := StateEvent{
-		TimerType: timerType,
-		NewState:  state,
+	current := sm.CurrentTimer()
+	if state == StateActive && sm.CurrentState() == StateActive && current != timerType {
+		return fmt.Errorf("%w: %s is running", errStrictMode, current)
 	}
-	sm.stateChan <- event
 
 	timer := sm.getTimer(timerType)
-	if nil == timer {
+	if timer == nil || sm.CurrentState() != StateActive || sm.CurrentTimer() != timerType {
+		return nil
+	}
+
+	switch {
+	case state == StatePaused && timer.Overtime() == 0:
+		return fmt.Errorf("%w: %s can't be paused", errStrictMode, timerType)
+	case state == StateFinished && timerType == BreakTimer && timer.TimeToFinish() > 0:
+		return fmt.Errorf("%w: break can't be skipped", errStrictMode)
+	}
+	return nil
+}
+
+// Strict tells if strict mode of active profile is on.
+func (sm *StateManager) Strict() bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.timerConfig.Strict
+}
+
+// setState changes state at moment at. Timer that finished by itself passes its deadline,
+// which is in the past after suspend, and time it spent asleep.
+func (sm *StateManager) setState(state TimerState, timerType TimerType, at time.Time, asleep time.Duration) {
+	// pause in overtime is the end of flow
+	if timer := sm.getTimer(timerType); state == StatePaused && timer != nil && timer.Overtime() > 0 {
+		state = StateFinished
+	}
+
+	sm.mu.Lock()
+	// only running timer can be paused, otherwise listeners wait for page that isn't refreshed
+	pauseIdle := state == StatePaused && (sm.currentState != StateActive || sm.currentTimer != timerType)
+	// finished timer may be followed by finish of another one that was left on pause
+	repeated := sm.currentState == state && (state != StateFinished || sm.currentTimer == timerType)
+	if repeated || pauseIdle {
+		sm.mu.Unlock()
 		return
 	}
 
-	switch state {
-	case StateActive:
-		sm.startTimer(timer)
-	case StatePaused:
-		sm.pauseTimer(timer)
-	case StateFinished:
-		if timerType == FocusTimer {
-			sm.completePomodoro()
+	sm.currentState = state
+	sm.currentTimer = timerType
+	if state == StatePaused {
+		sm.paused[timerType] = true
+	} else {
+		delete(sm.paused, timerType)
+	}
+	profile := sm.profile
+	sm.mu.Unlock()
+
+	var adjusted, overtime time.Duration
+	var skipped, abandoned, tooShort bool
+	if timer := sm.getTimer(timerType); timer != nil {
+		adjusted = timer.Adjusted()
+		overtime = timer.Overtime()
+		// interval finished with time left on it was skipped by hand,
+		// focus stopped early in strict mode is abandoned
+		skipped = state == StateFinished && timer.TimeToFinish() > 0
+		if skipped && timerType.IsFocus() && sm.Strict() {
+			skipped, abandoned = false, true
+		}
+		tooShort = state == StateFinished && timerType.IsFocus() && sm.focused(timer) < sm.MinFocus()
+		switch state {
+		case StateActive:
+			sm.startTimer(timer)
+		case StatePaused:
+			sm.pauseTimer(timer)
+		case StateFinished:
+			if timerType.IsFocus() && !skipped && !abandoned && !tooShort {
+				sm.completePomodoro()
+			}
+			elapsed := timer.Elapsed()
+			sm.finishTimer(timer)
+			if timerType == StopwatchTimer {
+				sm.setBreakAfterFlow(elapsed)
+			}
 		}
-		sm.finishTimer(timer)
+	}
+
+	// timer is updated before event is sent, so listeners see its new state
+	// and may start next interval without racing with reset
+	sm.stateChan <- StateEvent{
+		TimerType: timerType,
+		NewState:  state,
+		Profile:   profile,
+		At:        at,
+		Asleep:    asleep,
+		Adjusted:  adjusted,
+		Overtime:  overtime,
+		Skipped:   skipped,
+		Abandoned: abandoned,
+		TooShort:  tooShort,
 	}
 }
 
 func (sm *StateManager) completePomodoro() {
+	// tasks aren't tracked
+	if sm.taskManager == nil {
+		return
+	}
 	err := sm.taskManager.IncPomodoroActiveTask()
 	if err != nil {
 		sm.logger.Error("can't increment pomodoro in active task", slog.Any("error", err))
@@ -89,24 +226,171 @@ func (sm *StateManager) startTimer(timer *Timer) {
 
 	go func() {
 		_, ok := <-finishChan
-		if ok {
-			sm.SetState(StateFinished, timer.timerType)
+		if !ok {
+			return
+		}
+		if sm.flowMode(timer) {
+			sm.startOvertime(timer)
+			return
 		}
+		sm.setState(StateFinished, timer.timerType, timer.Deadline(), timer.Asleep())
 	}()
 }
 
+// setBreakAfterFlow sizes next break to stopwatch session by flowtime rules.
+// Break gets its length from config again after it finishes.
+func (sm *StateManager) setBreakAfterFlow(focus time.Duration) {
+	sm.mu.Lock()
+	breakDuration := sm.flowtime.BreakFor(focus)
+	sm.intervals[BreakTimer] = breakDuration
+	sm.mu.Unlock()
+
+	sm.breakTimer.Reset(breakDuration)
+}
+
+// SetFlowtime sets rules of break after stopwatch session.
+func (sm *StateManager) SetFlowtime(cfg config.FlowtimeConfig) {
+	sm.mu.Lock()
+	sm.flowtime = cfg
+	sm.mu.Unlock()
+}
+
+// SetMinimum sets shortest focus session that counts as pomodoro.
+func (sm *StateManager) SetMinimum(cfg config.MinimumConfig) {
+	sm.mu.Lock()
+	sm.minimum = cfg
+	sm.mu.Unlock()
+}
+
+// MinFocus returns shortest focus session that counts, zero if any session does.
+func (sm *StateManager) MinFocus() time.Duration {
+	focus := sm.duration(FocusTimer)
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.minimum.For(focus)
+}
+
+// focused returns how long focus timer has counted: stopwatch measures it,
+// countdown has spent its full length less remaining time plus overtime.
+func (sm *StateManager) focused(timer *Timer) time.Duration {
+	if timer.timerType == StopwatchTimer {
+		return timer.Elapsed()
+	}
+	return max(timer.Length()-timer.TimeToFinish()+timer.Overtime(), 0)
+}
+
+// BreakAfterFlow returns break that stopwatch session of given length earns.
+func (sm *StateManager) BreakAfterFlow(focus time.Duration) time.Duration {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.flowtime.BreakFor(focus)
+}
+
+// elapsed returns time counted by stopwatch.
+func (sm *StateManager) elapsed(timerType TimerType) time.Duration {
+	return sm.getTimer(timerType).Elapsed()
+}
+
+// flowMode reports whether timer should count past zero. Session that ran out
+// during suspend is finished as usual, nobody was working.
+func (sm *StateManager) flowMode(timer *Timer) bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return timer.timerType == FocusTimer && sm.timerConfig.FlowMode && timer.Asleep() == 0
+}
+
+// startOvertime tells listeners that timer reached zero but keeps running.
+func (sm *StateManager) startOvertime(timer *Timer) {
+	sm.mu.RLock()
+	profile := sm.profile
+	sm.mu.RUnlock()
+
+	sm.stateChan <- StateEvent{
+		TimerType: timer.timerType,
+		NewState:  StateOvertime,
+		Profile:   profile,
+		At:        timer.Deadline(),
+		Asleep:    0,
+		Adjusted:  timer.Adjusted(),
+		Overtime:  0,
+		Skipped:   false,
+		Abandoned: false,
+		TooShort:  false,
+	}
+}
+
+// overtime returns how long timer counts past zero in flow mode.
+func (sm *StateManager) overtime(timerType TimerType) time.Duration {
+	return sm.getTimer(timerType).Overtime()
+}
+
 func (sm *StateManager) finishTimer(timer *Timer) {
 	timer.Stop()
 
-	var duration time.Duration
-	switch timer.timerType {
+	sm.mu.Lock()
+	delete(sm.intervals, timer.timerType)
+	sm.mu.Unlock()
+
+	timer.Reset(sm.duration(timer.timerType))
+}
+
+// StartInterval runs timer for duration that differs from config, e.g. a step of session plan.
+// After finish timer gets its length from config again. Strict mode rules apply as in SetState.
+func (sm *StateManager) StartInterval(timerType TimerType, duration time.Duration) error {
+	if err := sm.checkStrict(StateActive, timerType); err != nil {
+		return err
+	}
+	sm.finishPaused()
+
+	sm.mu.Lock()
+	sm.intervals[timerType] = duration
+	sm.mu.Unlock()
+
+	sm.getTimer(timerType).Reset(duration)
+	sm.setState(StateActive, timerType, time.Now(), 0)
+	return nil
+}
+
+// finishPaused finishes sessions left on pause, as if they were skipped. Interval resets its timer
+// and plan goes on without the others, so these sessions would stay open otherwise.
+func (sm *StateManager) finishPaused() {
+	for _, timerType := range []TimerType{FocusTimer, BreakTimer, StopwatchTimer} {
+		sm.mu.RLock()
+		paused := sm.paused[timerType]
+		sm.mu.RUnlock()
+		if paused {
+			sm.setState(StateFinished, timerType, time.Now(), 0)
+		}
+	}
+}
+
+// AdjustTimer adds minutes to running or paused timer, negative duration removes them.
+// Returns change that was applied, in strict mode timer can't be shortened.
+func (sm *StateManager) AdjustTimer(timerType TimerType, d time.Duration) time.Duration {
+	if d < 0 && sm.Strict() {
+		return 0
+	}
+	return sm.getTimer(timerType).Adjust(d)
+}
+
+// duration returns full length of timer: running plan step or value from config.
+func (sm *StateManager) duration(timerType TimerType) time.Duration {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	if interval, ok := sm.intervals[timerType]; ok {
+		return interval
+	}
+	return durationFromConfig(sm.timerConfig, timerType)
+}
+
+func durationFromConfig(cfg config.TimerConfig, timerType TimerType) time.Duration {
+	switch timerType {
 	case FocusTimer:
-		duration = sm.timerConfig.FocusDuration
+		return cfg.FocusDuration
 	case BreakTimer:
-		duration = sm.timerConfig.BreakDuration
+		return cfg.BreakDuration
 	}
-
-	timer.Reset(duration)
+	return 0
 }
 
 func (sm *StateManager) getTimer(timerType TimerType) *Timer {
@@ -115,14 +399,56 @@ func (sm *StateManager) getTimer(timerType TimerType) *Timer {
 		return sm.focusTimer
 	case BreakTimer:
 		return sm.breakTimer
+	case StopwatchTimer:
+		return sm.stopwatch
 	}
 	return nil
 }
 
 func (sm *StateManager) timeToFinish(timerType TimerType) time.Duration {
-	return sm.getTimer(timerType).timeToFinish
+	return sm.getTimer(timerType).TimeToFinish()
+}
+
+// length returns full length of current interval, with time added by hand.
+func (sm *StateManager) length(timerType TimerType) time.Duration {
+	return sm.getTimer(timerType).Length()
 }
 
 func (sm *StateManager) IsFocusTimeHidden() bool {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
 	return sm.timerConfig.HiddenFocusTime
 }
+
+// UpdateTimerConfig applies new durations. Timers that weren't started yet get new length,
+// running and paused sessions keep their remaining time and use new length after finish.
+func (sm *StateManager) UpdateTimerConfig(cfg config.TimerConfig) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	for _, timerType := range []TimerType{FocusTimer, BreakTimer} {
+		timer := sm.getTimer(timerType)
+		running := sm.currentState == StateActive && sm.currentTimer == timerType
+		_, planStep := sm.intervals[timerType]
+		if !running && !planStep && timer.TimeToFinish() == durationFromConfig(sm.timerConfig, timerType) {
+			timer.Reset(durationFromConfig(cfg, timerType))
+		}
+	}
+	sm.timerConfig = cfg
+}
+
+// SetProfile switches timer settings to named profile.
+func (sm *StateManager) SetProfile(name string, cfg config.TimerConfig) {
+	sm.UpdateTimerConfig(cfg)
+
+	sm.mu.Lock()
+	sm.profile = name
+	sm.mu.Unlock()
+}
+
+// Profile returns name of the active timer profile.
+func (sm *StateManager) Profile() string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.profile
+}
@@ -45,6 +45,25 @@ func TestStateManager_SetState(t *testing.T) {
 	focusTimer.Stop()
 }
 
+func TestStateManager_PauseIdle(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration}, nil)
+
+	go func() {
+		for range stateChan {
+		}
+	}()
+	stateManager.SetState(StateActive, FocusTimer)
+	stateManager.SetState(StateFinished, FocusTimer)
+
+	// nobody receives event of pause that wasn't possible
+	require.NoError(t, stateManager.SetState(StatePaused, FocusTimer))
+	assert.Equal(t, StateFinished, stateManager.CurrentState())
+	close(stateChan)
+}
+
 func TestStateManager_StartTimer(t *testing.T) {
 	focusTimer, breakTimer := NewFocusTimer(2*time.Second), NewBreakTimer(breakDuration)
 	stateChan := make(chan StateEvent)
@@ -86,3 +105,151 @@ func TestStateManager_FinishTimer(t *testing.T) {
 
 	assert.Equal(t, focusDuration, focusTimer.timeToFinish)
 }
+
+func TestStateManager_UpdateTimerConfig(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(7*time.Second), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, HiddenFocusTime: false},
+		nil)
+
+	stateManager.UpdateTimerConfig(config.TimerConfig{
+		FocusDuration: 20 * time.Second, BreakDuration: 5 * time.Second, HiddenFocusTime: true,
+	})
+
+	// paused focus session keeps remaining time, untouched break timer gets new length
+	assert.Equal(t, 7*time.Second, focusTimer.TimeToFinish())
+	assert.Equal(t, 5*time.Second, breakTimer.TimeToFinish())
+	assert.True(t, stateManager.IsFocusTimeHidden())
+	assert.Equal(t, 20*time.Second, stateManager.duration(FocusTimer))
+}
+
+func TestStateManager_SetProfile(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 1)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, HiddenFocusTime: false},
+		nil)
+	assert.Equal(t, config.DefaultProfile, stateManager.Profile())
+
+	stateManager.SetProfile("deep work", config.TimerConfig{
+		FocusDuration: 50 * time.Minute, BreakDuration: 10 * time.Minute, HiddenFocusTime: false,
+	})
+	assert.Equal(t, "deep work", stateManager.Profile())
+	assert.Equal(t, 50*time.Minute, focusTimer.TimeToFinish())
+
+	stateManager.SetState(StateFinished, BreakTimer)
+	event := <-stateChan
+	assert.Equal(t, "deep work", event.Profile)
+	assert.Equal(t, 10*time.Minute, breakTimer.TimeToFinish())
+}
+
+func TestStateManager_FlowMode(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(1*time.Second), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 3)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, FlowMode: true},
+		nil)
+
+	stateManager.SetState(StateActive, FocusTimer)
+	assert.Equal(t, StateActive, (<-stateChan).NewState)
+
+	// timer reaches zero and keeps counting
+	event := <-stateChan
+	assert.Equal(t, StateOvertime, event.NewState)
+	assert.Equal(t, StateActive, stateManager.CurrentState())
+	time.Sleep(1 * time.Second)
+	assert.Positive(t, stateManager.overtime(FocusTimer))
+
+	// pause in overtime finishes session
+	stateManager.SetState(StatePaused, FocusTimer)
+	event = <-stateChan
+	assert.Equal(t, StateFinished, event.NewState)
+	assert.GreaterOrEqual(t, event.Overtime, 1*time.Second)
+	assert.Equal(t, focusDuration, stateManager.timeToFinish(FocusTimer))
+}
+
+func TestStateManager_Stopwatch(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 2)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration}, nil)
+	stateManager.SetFlowtime(config.FlowtimeConfig{Divisor: 2, MinBreak: time.Second})
+
+	stateManager.SetState(StateActive, StopwatchTimer)
+	time.Sleep(2 * time.Second)
+	assert.Equal(t, 2*time.Second, stateManager.elapsed(StopwatchTimer))
+
+	// break is sized to the session
+	stateManager.SetState(StateFinished, StopwatchTimer)
+	assert.Equal(t, StopwatchTimer, (<-stateChan).TimerType)
+	assert.Equal(t, StateFinished, (<-stateChan).NewState)
+	assert.Equal(t, time.Second, stateManager.timeToFinish(BreakTimer))
+	assert.Equal(t, time.Second, stateManager.duration(BreakTimer))
+	assert.Equal(t, 0*time.Second, stateManager.elapsed(StopwatchTimer))
+}
+
+func TestStateManager_Skip(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 2)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, HiddenFocusTime: true},
+		nil)
+
+	stateManager.SetState(StateActive, FocusTimer)
+	assert.False(t, (<-stateChan).Skipped)
+
+	stateManager.SetState(StateFinished, FocusTimer)
+	event := <-stateChan
+	assert.Equal(t, StateFinished, event.NewState)
+	assert.True(t, event.Skipped)
+}
+
+func TestStateManager_Strict(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 4)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration, Strict: true},
+		nil)
+
+	require.NoError(t, stateManager.SetState(StateActive, FocusTimer))
+	<-stateChan
+
+	require.ErrorIs(t, stateManager.SetState(StatePaused, FocusTimer), errStrictMode)
+	assert.Equal(t, StateActive, stateManager.CurrentState())
+	assert.Zero(t, stateManager.AdjustTimer(FocusTimer, -time.Second))
+
+	// running focus can't be left for another timer, plan steps included
+	require.ErrorIs(t, stateManager.SetState(StateActive, BreakTimer), errStrictMode)
+	require.ErrorIs(t, stateManager.StartInterval(StopwatchTimer, 0), errStrictMode)
+	assert.Equal(t, FocusTimer, stateManager.CurrentTimer())
+
+	require.NoError(t, stateManager.SetState(StateFinished, FocusTimer))
+	event := <-stateChan
+	assert.True(t, event.Abandoned)
+	assert.False(t, event.Skipped)
+
+	require.NoError(t, stateManager.SetState(StateActive, BreakTimer))
+	<-stateChan
+	require.ErrorIs(t, stateManager.SetState(StateFinished, BreakTimer), errStrictMode)
+	assert.Equal(t, StateActive, stateManager.CurrentState())
+
+	breakTimer.Stop()
+}
+
+func TestStateManager_MinFocus(t *testing.T) {
+	focusTimer, breakTimer := NewFocusTimer(focusDuration), NewBreakTimer(breakDuration)
+	stateChan := make(chan StateEvent, 2)
+	stateManager := NewStateManager(slog.Default(), focusTimer, breakTimer, stateChan,
+		config.TimerConfig{FocusDuration: focusDuration, BreakDuration: breakDuration},
+		nil)
+	stateManager.SetMinimum(config.MinimumConfig{Percent: 50})
+	assert.Equal(t, focusDuration/2, stateManager.MinFocus())
+
+	stateManager.SetState(StateActive, FocusTimer)
+	<-stateChan
+	stateManager.SetState(StateFinished, FocusTimer)
+	event := <-stateChan
+	assert.True(t, event.TooShort)
+	assert.True(t, event.Skipped)
+}
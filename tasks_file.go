@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+var (
+	errTaskNotFound = errors.New("task not found")
+	errNoActiveTask = errors.New("no active task")
+)
+
+// taskLineFormat reads and writes tasks kept one per line of text file.
+type taskLineFormat interface {
+	// parse returns task of line without ID, false if line isn't a task.
+	parse(line string) (*Task, bool)
+	// update writes task fields to line, other content of line is kept.
+	update(line string, task *Task) string
+	// format returns line of new task.
+	format(task *Task) string
+}
+
+// FileTasks keeps tasks in text file other programs may edit. ID of task is its line number.
+// File is read on every call, so edits made elsewhere are picked up, and lines that
+// aren't tasks or aren't changed are written back as they are.
+type FileTasks struct {
+	path   string
+	format taskLineFormat
+	mu     sync.Mutex
+}
+
+func NewFileTasks(path string, format taskLineFormat) *FileTasks {
+	return &FileTasks{
+		path:   path,
+		format: format,
+		mu:     sync.Mutex{},
+	}
+}
+
+// Path returns file tasks are kept in.
+func (t *FileTasks) Path() string {
+	return t.path
+}
+
+func (t *FileTasks) Tasks() ([]*Task, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*Task
+	for i := range file.lines {
+		if task, ok := file.task(i + 1); ok {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+// CreateTask adds task to the end of file.
+func (t *FileTasks) CreateTask(task *Task) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return err
+	}
+
+	file.lines = append(file.lines, t.format.format(task))
+	task.ID, task.line = len(file.lines), file.lines[len(file.lines)-1]
+	return t.write(file)
+}
+
+// DeleteTask removes task line, task read from file is looked up like in UpdateTask.
+func (t *FileTasks) DeleteTask(task *Task) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return err
+	}
+	id, ok := file.find(task)
+	if !ok {
+		return fmt.Errorf("can't delete task %d: %w", task.ID, errTaskNotFound)
+	}
+
+	file.lines = append(file.lines[:id-1], file.lines[id:]...)
+	return t.write(file)
+}
+
+// UpdateTask writes task to its line. Task read from file is looked up by the line it was read from,
+// so it's updated even if lines above were added or removed elsewhere, and isn't written over
+// another task if its own line was changed or removed.
+func (t *FileTasks) UpdateTask(task *Task) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return err
+	}
+	id, ok := file.find(task)
+	if !ok {
+		return fmt.Errorf("can't update task %d: %w", task.ID, errTaskNotFound)
+	}
+
+	file.lines[id-1] = t.format.update(file.lines[id-1], task)
+	task.ID, task.line = id, file.lines[id-1]
+	return t.write(file)
+}
+
+func (t *FileTasks) ActiveTask() (*Task, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return nil, err
+	}
+	return file.active()
+}
+
+// ActiveTaskName returns name of active task, empty if there is none.
+func (t *FileTasks) ActiveTaskName() (string, error) {
+	task, err := t.ActiveTask()
+	if errors.Is(err, errNoActiveTask) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return task.Name, nil
+}
+
+// IncPomodoroActiveTask counts pomodoro to active task. Task with estimate is completed
+// when estimate is reached, task without estimate stays open.
+func (t *FileTasks) IncPomodoroActiveTask() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	file, err := t.read()
+	if err != nil {
+		return err
+	}
+	task, err := file.active()
+	if errors.Is(err, errNoActiveTask) {
+		return nil
+	}
+
+	task.PomodorosCompleted++
+	if task.PomodorosRequired > 0 && task.PomodorosCompleted >= task.PomodorosRequired {
+		task.IsComplete = true
+	}
+	file.lines[task.ID-1] = t.format.update(file.lines[task.ID-1], task)
+	return t.write(file)
+}
+
+// tasksFile is content of tasks file, line ending and final newline are kept on write.
+type tasksFile struct {
+	lines        []string
+	format       taskLineFormat
+	eol          string
+	finalNewline bool
+}
+
+// task returns task with ID, false if there is no such task line.
+func (f *tasksFile) task(id int) (*Task, bool) {
+	if id <= 0 || id > len(f.lines) {
+		return nil, false
+	}
+	task, ok := f.format.parse(f.lines[id-1])
+	if !ok {
+		return nil, false
+	}
+	task.ID, task.line = id, f.lines[id-1]
+	return task, true
+}
+
+// find returns line number of task. Task read from file is found by its line, the first
+// equal line is used if it moved; task that wasn't read from file is found by ID.
+func (f *tasksFile) find(task *Task) (int, bool) {
+	if task.line == "" {
+		_, ok := f.task(task.ID)
+		return task.ID, ok
+	}
+	if task.ID > 0 && task.ID <= len(f.lines) && f.lines[task.ID-1] == task.line {
+		return task.ID, true
+	}
+	for i, line := range f.lines {
+		if _, ok := f.task(i + 1); ok && line == task.line {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// active returns the first active task.
+func (f *tasksFile) active() (*Task, error) {
+	for i := range f.lines {
+		if task, ok := f.task(i + 1); ok && task.IsActive {
+			return task, nil
+		}
+	}
+	return nil, errNoActiveTask
+}
+
+// read parses tasks file, missing file has no tasks.
+func (t *FileTasks) read() (*tasksFile, error) {
+	file := &tasksFile{lines: nil, format: t.format, eol: "\n", finalNewline: true}
+
+	data, err := os.ReadFile(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't read tasks file: %w", err)
+	}
+	if len(data) == 0 {
+		return file, nil
+	}
+
+	content := string(data)
+	if strings.Contains(content, "\r\n") {
+		file.eol = "\r\n"
+	}
+	content, file.finalNewline = strings.CutSuffix(content, file.eol)
+	file.lines = strings.Split(content, file.eol)
+	return file, nil
+}
+
+func (t *FileTasks) write(file *tasksFile) error {
+	content := strings.Join(file.lines, file.eol)
+	if file.finalNewline && len(file.lines) > 0 {
+		content += file.eol
+	}
+
+	if err := os.WriteFile(t.path, []byte(content), 0o600); err != nil {
+		return fmt.Errorf("can't write tasks file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+const tasksPollInterval = 1 * time.Second
+
+// newTaskSource returns tasks of configured source, database by default.
+func newTaskSource(storage *Storage, cfg config.TasksConfig) taskManager {
+	switch cfg.Source {
+	case config.TaskSourceTodoTxt:
+		return NewTodoTxtTasks(cfg.Path())
+	case config.TaskSourceMarkdown:
+		return NewMarkdownTasks(cfg.Path())
+	}
+	return storage
+}
+
+// WatchTasks refreshes tasks page when tasks file is changed, blocks forever.
+// It does nothing for tasks kept in database.
+func (m *UIManager) WatchTasks() {
+	source, ok := m.taskTracker.(*FileTasks)
+	if !ok {
+		return
+	}
+	watchTasksFile(source.Path(), tasksPollInterval, nil, func() {
+		m.ui.QueueUpdateDraw(func() {
+			// forms are left alone, refresh would drop what is typed
+			if m.currentPage != nil && m.currentPage.name == allTasksPage {
+				m.AddPageAndSwitch(m.NewTasksPage())
+			}
+		})
+	})
+}
+
+func watchTasksFile(path string, interval time.Duration, stop <-chan struct{}, onChange func()) {
+	lastMod, lastSize := tasksFileVersion(path)
+
+	tick := time.NewTicker(interval)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+		case <-stop:
+			return
+		}
+
+		modTime, size := tasksFileVersion(path)
+		if modTime.Equal(lastMod) && size == lastSize {
+			continue
+		}
+		lastMod, lastSize = modTime, size
+		onChange()
+	}
+}
+
+func tasksFileVersion(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+const (
+	taskwarriorTimeFormat = "20060102T150405Z"
+	taskwarriorPending    = "pending"
+	taskwarriorCompleted  = "completed"
+	// defaultTaskwarriorEstimate is estimate of task without "pomodoros" attribute, like in task form.
+	defaultTaskwarriorEstimate = 1
+)
+
+var (
+	errUnknownCommand = errors.New("unknown command")
+	errTaskSource     = errors.New("tasks aren't kept in database")
+)
+
+// taskwarriorTask is task of Taskwarrior JSON. Pomodoros and PomodorosDone are UDA fields,
+// they have to be declared in .taskrc to be kept by Taskwarrior.
+type taskwarriorTask struct {
+	UUID          string            `json:"uuid"`
+	Description   string            `json:"description"`
+	Status        string            `json:"status"`
+	Entry         string            `json:"entry,omitempty"`
+	Modified      string            `json:"modified,omitempty"`
+	End           string            `json:"end,omitempty"`
+	Pomodoros     *taskwarriorCount `json:"pomodoros,omitempty"`
+	PomodorosDone *taskwarriorCount `json:"pomodorosdone,omitempty"`
+}
+
+// taskwarriorCount is numeric UDA, Taskwarrior may write it as number or string.
+type taskwarriorCount int
+
+func (c *taskwarriorCount) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseFloat(string(bytes.Trim(data, `"`)), 64)
+	if err != nil {
+		return fmt.Errorf("can't parse pomodoros count: %w", err)
+	}
+	*c = taskwarriorCount(value)
+	return nil
+}
+
+func newTaskwarriorCount(n int) *taskwarriorCount {
+	count := taskwarriorCount(n)
+	return &count
+}
+
+// runTaskwarrior handles "pomotrack taskwarrior import|export".
+func runTaskwarrior(logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: taskwarrior needs import or export", errUnknownCommand)
+	}
+
+	flags := flag.NewFlagSet("taskwarrior "+args[0], flag.ContinueOnError)
+	file := flags.String("file", "", "read from or write to file instead of stdin and stdout")
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("can't parse taskwarrior flags: %w", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("can't load config: %w", err)
+	}
+	// tasks file has no place for UUID, so only tasks kept in database are synced
+	if cfg.Tasks.Source != "" {
+		return fmt.Errorf("%w: tasks source is %s", errTaskSource, cfg.Tasks.Source)
+	}
+
+	storage, err := openStorage(logger)
+	if err != nil {
+		return err
+	}
+	defer storage.DB.Close()
+
+	switch args[0] {
+	case "import":
+		var r io.Reader = os.Stdin
+		if *file != "" {
+			f, err := os.Open(*file)
+			if err != nil {
+				return fmt.Errorf("can't open taskwarrior file: %w", err)
+			}
+			defer f.Close()
+			r = f
+		}
+		created, updated, err := importTaskwarrior(storage, r)
+		fmt.Fprintf(os.Stderr, "created %d tasks, updated %d\n", created, updated)
+		return err
+	case "export":
+		if *file == "" {
+			return exportTaskwarrior(storage, os.Stdout)
+		}
+		return writeFile(*file, func(w io.Writer) error {
+			return exportTaskwarrior(storage, w)
+		})
+	}
+	return fmt.Errorf("%w: taskwarrior %s", errUnknownCommand, args[0])
+}
+
+// importTaskwarrior creates tasks for pending Taskwarrior tasks and updates name and estimate
+// of tasks imported before, progress is kept by PomoTrack. Task is complete when estimate is reached,
+// like after pomodoro. It returns created and updated counts.
+func importTaskwarrior(storage *Storage, r io.Reader) (int, int, error) {
+	imported, err := decodeTaskwarrior(r)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	tasks, err := storage.Tasks()
+	if err != nil {
+		return 0, 0, err
+	}
+	byUUID := make(map[string]*Task, len(tasks))
+	for _, task := range tasks {
+		if task.UUID != "" {
+			byUUID[task.UUID] = task
+		}
+	}
+
+	var created, updated int
+	for _, twTask := range imported {
+		if twTask.Status != taskwarriorPending || twTask.UUID == "" {
+			continue
+		}
+
+		if task, ok := byUUID[twTask.UUID]; ok {
+			task.Name = twTask.Description
+			if twTask.Pomodoros != nil {
+				task.PomodorosRequired = int(*twTask.Pomodoros)
+				task.IsComplete = estimateReached(task.PomodorosCompleted, task.PomodorosRequired)
+			}
+			if err = storage.UpdateTask(task); err != nil {
+				return created, updated, err
+			}
+			updated++
+			continue
+		}
+
+		if err = storage.CreateTask(newTaskFromTaskwarrior(twTask)); err != nil {
+			return created, updated, err
+		}
+		created++
+	}
+	return created, updated, nil
+}
+
+func newTaskFromTaskwarrior(twTask taskwarriorTask) *Task {
+	required, completed := defaultTaskwarriorEstimate, 0
+	if twTask.Pomodoros != nil {
+		required = int(*twTask.Pomodoros)
+	}
+	if twTask.PomodorosDone != nil {
+		completed = int(*twTask.PomodorosDone)
+	}
+	entry, err := time.Parse(taskwarriorTimeFormat, twTask.Entry)
+	if err != nil {
+		entry = time.Now()
+	}
+
+	return &Task{
+		ID:                 -1,
+		Name:               twTask.Description,
+		PomodorosRequired:  required,
+		PomodorosCompleted: completed,
+		IsComplete:         estimateReached(completed, required),
+		IsActive:           false,
+		CreateAt:           entry,
+		UUID:               twTask.UUID,
+		CompleteAt:         time.Time{},
+		line:               "",
+	}
+}
+
+// estimateReached reports whether task is complete, zero estimate means task has none.
+func estimateReached(completed, required int) bool {
+	return required > 0 && completed >= required
+}
+
+// decodeTaskwarrior reads output of "task export": JSON array or, in old versions, one object per line.
+func decodeTaskwarrior(r io.Reader) ([]taskwarriorTask, error) {
+	var tasks []taskwarriorTask
+
+	decoder := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can't decode taskwarrior tasks: %w", err)
+		}
+
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 && raw[0] == '[' {
+			var list []taskwarriorTask
+			if err = json.Unmarshal(raw, &list); err != nil {
+				return nil, fmt.Errorf("can't decode taskwarrior tasks: %w", err)
+			}
+			tasks = append(tasks, list...)
+			continue
+		}
+
+		var task taskwarriorTask
+		if err = json.Unmarshal(raw, &task); err != nil {
+			return nil, fmt.Errorf("can't decode taskwarrior task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+}
+
+// exportTaskwarrior writes tasks as JSON for "task import". Tasks created in PomoTrack get UUID
+// on the first export, so next exports update the same Taskwarrior tasks.
+func exportTaskwarrior(storage *Storage, w io.Writer) error {
+	tasks, err := storage.Tasks()
+	if err != nil {
+		return err
+	}
+
+	exported := make([]taskwarriorTask, 0, len(tasks))
+	for _, task := range tasks {
+		if task.UUID == "" {
+			if task.UUID, err = newUUID(); err != nil {
+				return err
+			}
+			if err = storage.UpdateTask(task); err != nil {
+				return err
+			}
+		}
+		exported = append(exported, taskToTaskwarrior(task))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(exported); err != nil {
+		return fmt.Errorf("can't write taskwarrior tasks: %w", err)
+	}
+	return nil
+}
+
+// taskToTaskwarrior converts task, completed task ends when it became complete. Modified is left
+// to Taskwarrior, so exports of unchanged task don't change it.
+func taskToTaskwarrior(task *Task) taskwarriorTask {
+	twTask := taskwarriorTask{
+		UUID:          task.UUID,
+		Description:   task.Name,
+		Status:        taskwarriorPending,
+		Entry:         task.CreateAt.UTC().Format(taskwarriorTimeFormat),
+		Modified:      "",
+		End:           "",
+		Pomodoros:     newTaskwarriorCount(task.PomodorosRequired),
+		PomodorosDone: newTaskwarriorCount(task.PomodorosCompleted),
+	}
+	if task.IsComplete {
+		twTask.Status = taskwarriorCompleted
+		if !task.CompleteAt.IsZero() {
+			twTask.End = task.CompleteAt.UTC().Format(taskwarriorTimeFormat)
+		}
+	}
+	return twTask
+}
+
+// newUUID returns random UUID version 4.
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("can't generate uuid: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
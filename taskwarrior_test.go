@@ -0,0 +1,132 @@
+//nolint:exhaustruct,errcheck // test data
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskwarriorExport = `[
+{"id":1,"description":"Write report","entry":"20241007T100000Z","modified":"20241007T100000Z",
+ "status":"pending","uuid":"a3f1c0de-0000-4000-8000-000000000001","pomodoros":3,"urgency":1.2},
+{"id":2,"description":"Read book","entry":"20241006T080000Z","status":"pending",
+ "uuid":"a3f1c0de-0000-4000-8000-000000000002","tags":["fun"]},
+{"id":0,"description":"Old one","entry":"20241001T080000Z","status":"completed",
+ "uuid":"a3f1c0de-0000-4000-8000-000000000003"}
+]`
+
+func TestImportTaskwarrior(t *testing.T) {
+	defer clearTable()
+
+	created, updated, err := importTaskwarrior(s, strings.NewReader(taskwarriorExport))
+	require.NoError(t, err)
+	assert.Equal(t, 2, created)
+	assert.Equal(t, 0, updated)
+
+	tasks, err := s.Tasks()
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Read book", tasks[0].Name)
+	assert.Equal(t, defaultTaskwarriorEstimate, tasks[0].PomodorosRequired)
+	assert.Equal(t, "a3f1c0de-0000-4000-8000-000000000001", tasks[1].UUID)
+	assert.Equal(t, 3, tasks[1].PomodorosRequired)
+
+	// old versions export one object per line
+	tasks[1].PomodorosCompleted = 2
+	require.NoError(t, s.UpdateTask(tasks[1]))
+	line := `{"description":"Write final report","status":"pending","uuid":"a3f1c0de-0000-4000-8000-000000000001",` +
+		`"pomodoros":"4"}` + "\n"
+	created, updated, err = importTaskwarrior(s, strings.NewReader(line))
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, 1, updated)
+
+	tasks, err = s.Tasks()
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, "Write final report", tasks[1].Name)
+	assert.Equal(t, 4, tasks[1].PomodorosRequired)
+	assert.Equal(t, 2, tasks[1].PomodorosCompleted)
+	assert.False(t, tasks[1].IsComplete)
+
+	// lowered estimate completes task
+	line = `{"description":"Write final report","status":"pending","uuid":"a3f1c0de-0000-4000-8000-000000000001",` +
+		`"pomodoros":2}`
+	_, _, err = importTaskwarrior(s, strings.NewReader(line))
+	require.NoError(t, err)
+	tasks, err = s.Tasks()
+	require.NoError(t, err)
+	assert.True(t, tasks[1].IsComplete)
+
+	// zero estimate means task has none
+	line = `{"description":"Someday","status":"pending","uuid":"a3f1c0de-0000-4000-8000-000000000004",` +
+		`"pomodoros":0}`
+	_, _, err = importTaskwarrior(s, strings.NewReader(line))
+	require.NoError(t, err)
+	line = `{"description":"Read book","status":"pending","uuid":"a3f1c0de-0000-4000-8000-000000000002",` +
+		`"pomodoros":0}`
+	_, _, err = importTaskwarrior(s, strings.NewReader(line))
+	require.NoError(t, err)
+	tasks, err = s.Tasks()
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, 0, tasks[0].PomodorosRequired)
+	assert.False(t, tasks[0].IsComplete)
+	assert.Equal(t, "Someday", tasks[2].Name)
+	assert.False(t, tasks[2].IsComplete)
+}
+
+func TestExportTaskwarrior(t *testing.T) {
+	defer clearTable()
+
+	_, _, err := importTaskwarrior(s, strings.NewReader(taskwarriorExport))
+	require.NoError(t, err)
+	require.NoError(t, s.CreateTask(&Task{Name: "Plan week", PomodorosRequired: 1, PomodorosCompleted: 1,
+		IsComplete: true}))
+
+	var buf bytes.Buffer
+	require.NoError(t, exportTaskwarrior(s, &buf))
+
+	var exported []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	require.Len(t, exported, 3)
+
+	// next export doesn't touch end and modified of unchanged tasks
+	var next bytes.Buffer
+	require.NoError(t, exportTaskwarrior(s, &next))
+	assert.Equal(t, buf.String(), next.String())
+
+	byName := make(map[string]map[string]any)
+	for _, task := range exported {
+		byName[task["description"].(string)] = task
+	}
+	report := byName["Write report"]
+	assert.Equal(t, "a3f1c0de-0000-4000-8000-000000000001", report["uuid"])
+	assert.Equal(t, "pending", report["status"])
+	assert.Equal(t, "20241007T100000Z", report["entry"])
+	assert.InDelta(t, 3, report["pomodoros"], 0)
+	assert.InDelta(t, 0, report["pomodorosdone"], 0)
+	assert.NotContains(t, report, "end")
+	assert.NotContains(t, report, "modified")
+
+	plan := byName["Plan week"]
+	assert.Equal(t, "completed", plan["status"])
+	end, err := time.Parse(taskwarriorTimeFormat, plan["end"].(string))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), end, time.Minute)
+	assert.InDelta(t, 1, plan["pomodorosdone"], 0)
+	assert.Regexp(t, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, plan["uuid"])
+
+	// generated UUID is kept, so next export updates the same task
+	tasks, err := s.Tasks()
+	require.NoError(t, err)
+	for _, task := range tasks {
+		assert.Equal(t, byName[task.Name]["uuid"], task.UUID)
+	}
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const defaultThemeName = "dark"
+
+var (
+	errUnknownTheme = errors.New("unknown theme")
+	errInvalidColor = errors.New("invalid color")
+)
+
+// themeStyle is a piece of tview markup: foreground, background and attributes.
+type themeStyle struct {
+	fg    tcell.Color
+	bg    tcell.Color
+	attrs string
+}
+
+// Tag returns tview color tag, e.g. "[red::b]".
+func (s themeStyle) Tag() string {
+	return fmt.Sprintf("[%s:%s:%s]", colorName(s.fg), colorName(s.bg), s.attrs)
+}
+
+// Wrap colors text and resets style after it.
+func (s themeStyle) Wrap(text string) string {
+	return s.Tag() + text + "[-:-:-]"
+}
+
+func colorName(c tcell.Color) string {
+	if c == tcell.ColorDefault {
+		return ""
+	}
+	return c.String()
+}
+
+type Theme struct {
+	Name   string
+	Styles tview.Theme
+
+	Focus      themeStyle
+	Break      themeStyle
+	PageActive themeStyle
+	PageOther  themeStyle
+	Muted      themeStyle
+	Danger     themeStyle
+	Highlight  themeStyle
+	Overtime   themeStyle
+
+	// Monochrome strips every color on screen and uses text attributes instead.
+	Monochrome bool
+}
+
+//nolint:exhaustruct // zero color is terminal default
+func builtinThemes() map[string]Theme {
+	return map[string]Theme{
+		"dark": {
+			Name: "dark",
+			Styles: tview.Theme{
+				PrimitiveBackgroundColor:    tcell.ColorBlack,
+				ContrastBackgroundColor:     tcell.ColorBlue,
+				MoreContrastBackgroundColor: tcell.ColorGreen,
+				BorderColor:                 tcell.ColorWhite,
+				TitleColor:                  tcell.ColorWhite,
+				GraphicsColor:               tcell.ColorWhite,
+				PrimaryTextColor:            tcell.ColorWhite,
+				SecondaryTextColor:          tcell.ColorYellow,
+				TertiaryTextColor:           tcell.ColorGreen,
+				InverseTextColor:            tcell.ColorBlue,
+				ContrastSecondaryTextColor:  tcell.ColorNavy,
+			},
+			Focus:      themeStyle{fg: tcell.ColorRed},
+			Break:      themeStyle{fg: tcell.ColorGreen},
+			PageActive: themeStyle{bg: tcell.ColorBrown},
+			PageOther:  themeStyle{bg: tcell.ColorGray},
+			Muted:      themeStyle{fg: tcell.ColorGray},
+			Danger:     themeStyle{fg: tcell.ColorRed},
+			Highlight:  themeStyle{fg: tcell.ColorGreen},
+			Overtime:   themeStyle{fg: tcell.ColorYellow},
+			Monochrome: false,
+		},
+		"light": {
+			Name: "light",
+			Styles: tview.Theme{
+				PrimitiveBackgroundColor:    tcell.ColorWhite,
+				ContrastBackgroundColor:     tcell.ColorSilver,
+				MoreContrastBackgroundColor: tcell.ColorLightSteelBlue,
+				BorderColor:                 tcell.ColorBlack,
+				TitleColor:                  tcell.ColorBlack,
+				GraphicsColor:               tcell.ColorBlack,
+				PrimaryTextColor:            tcell.ColorBlack,
+				SecondaryTextColor:          tcell.ColorNavy,
+				TertiaryTextColor:           tcell.ColorDarkGreen,
+				InverseTextColor:            tcell.ColorWhite,
+				ContrastSecondaryTextColor:  tcell.ColorDimGray,
+			},
+			Focus:      themeStyle{fg: tcell.ColorDarkRed},
+			Break:      themeStyle{fg: tcell.ColorDarkGreen},
+			PageActive: themeStyle{bg: tcell.ColorGold},
+			PageOther:  themeStyle{bg: tcell.ColorLightGray},
+			Muted:      themeStyle{fg: tcell.ColorDimGray},
+			Danger:     themeStyle{fg: tcell.ColorDarkRed},
+			Highlight:  themeStyle{fg: tcell.ColorDarkGreen},
+			Overtime:   themeStyle{fg: tcell.ColorDarkOrange},
+			Monochrome: false,
+		},
+		"high-contrast": {
+			Name: "high-contrast",
+			Styles: tview.Theme{
+				PrimitiveBackgroundColor:    tcell.ColorBlack,
+				ContrastBackgroundColor:     tcell.ColorWhite,
+				MoreContrastBackgroundColor: tcell.ColorYellow,
+				BorderColor:                 tcell.ColorWhite,
+				TitleColor:                  tcell.ColorWhite,
+				GraphicsColor:               tcell.ColorWhite,
+				PrimaryTextColor:            tcell.ColorWhite,
+				SecondaryTextColor:          tcell.ColorYellow,
+				TertiaryTextColor:           tcell.ColorAqua,
+				InverseTextColor:            tcell.ColorBlack,
+				ContrastSecondaryTextColor:  tcell.ColorBlack,
+			},
+			Focus:      themeStyle{fg: tcell.ColorYellow, attrs: "b"},
+			Break:      themeStyle{fg: tcell.ColorAqua, attrs: "b"},
+			PageActive: themeStyle{fg: tcell.ColorBlack, bg: tcell.ColorYellow},
+			PageOther:  themeStyle{fg: tcell.ColorBlack, bg: tcell.ColorWhite},
+			Muted:      themeStyle{fg: tcell.ColorSilver},
+			Danger:     themeStyle{fg: tcell.ColorFuchsia, attrs: "b"},
+			Highlight:  themeStyle{fg: tcell.ColorYellow, attrs: "u"},
+			Overtime:   themeStyle{fg: tcell.ColorLime, attrs: "b"},
+			Monochrome: false,
+		},
+		"monochrome": {
+			Name: "monochrome",
+			Styles: tview.Theme{
+				PrimitiveBackgroundColor:    tcell.ColorDefault,
+				ContrastBackgroundColor:     tcell.ColorGray,
+				MoreContrastBackgroundColor: tcell.ColorGray,
+				BorderColor:                 tcell.ColorDefault,
+				TitleColor:                  tcell.ColorDefault,
+				GraphicsColor:               tcell.ColorDefault,
+				PrimaryTextColor:            monochromeSelected,
+				SecondaryTextColor:          tcell.ColorDefault,
+				TertiaryTextColor:           tcell.ColorDefault,
+				InverseTextColor:            tcell.ColorDefault,
+				ContrastSecondaryTextColor:  tcell.ColorDefault,
+			},
+			Focus:      themeStyle{attrs: "b"},
+			Break:      themeStyle{attrs: "i"},
+			PageActive: themeStyle{attrs: "r"},
+			PageOther:  themeStyle{attrs: ""},
+			Muted:      themeStyle{attrs: "d"},
+			Danger:     themeStyle{attrs: "b"},
+			Highlight:  themeStyle{attrs: "u"},
+			Overtime:   themeStyle{attrs: "bu"},
+			Monochrome: true,
+		},
+	}
+}
+
+// themeNames returns names of built-in themes in alphabetical order.
+func themeNames() []string {
+	themes := builtinThemes()
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTheme picks built-in theme by name and applies custom colors from config.
+// NO_COLOR environment variable forces monochrome theme.
+func NewTheme(cfg config.ThemeConfig) (*Theme, error) {
+	themes := builtinThemes()
+
+	if os.Getenv("NO_COLOR") != "" {
+		theme := themes["monochrome"]
+		return &theme, nil
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = defaultThemeName
+	}
+	theme, ok := themes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownTheme, name)
+	}
+	if theme.Monochrome {
+		return &theme, nil
+	}
+
+	overrides := []struct {
+		value  string
+		target *tcell.Color
+	}{
+		{cfg.Background, &theme.Styles.PrimitiveBackgroundColor},
+		{cfg.Text, &theme.Styles.PrimaryTextColor},
+		{cfg.Border, &theme.Styles.BorderColor},
+		{cfg.Border, &theme.Styles.GraphicsColor},
+		{cfg.Contrast, &theme.Styles.ContrastBackgroundColor},
+		{cfg.Focus, &theme.Focus.fg},
+		{cfg.Break, &theme.Break.fg},
+		{cfg.PageActive, &theme.PageActive.bg},
+		{cfg.PageOther, &theme.PageOther.bg},
+		{cfg.Muted, &theme.Muted.fg},
+		{cfg.Danger, &theme.Danger.fg},
+		{cfg.Highlight, &theme.Highlight.fg},
+		{cfg.Overtime, &theme.Overtime.fg},
+	}
+	for _, o := range overrides {
+		if o.value == "" {
+			continue
+		}
+		color, err := parseColor(o.value)
+		if err != nil {
+			return nil, err
+		}
+		*o.target = color
+	}
+
+	return &theme, nil
+}
+
+func parseColor(value string) (tcell.Color, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if _, ok := tcell.ColorNames[value]; !ok && !strings.HasPrefix(value, "#") {
+		return tcell.ColorDefault, fmt.Errorf("%w: %s", errInvalidColor, value)
+	}
+	color := tcell.GetColor(value)
+	if color == tcell.ColorDefault {
+		return tcell.ColorDefault, fmt.Errorf("%w: %s", errInvalidColor, value)
+	}
+	return color, nil
+}
+
+// Apply sets default colors of tview primitives, must be called before any primitive is created.
+func (t *Theme) Apply() {
+	tview.Styles = t.Styles
+}
+
+// monochromeSelected marks background of selected items (focused button, list item)
+// so monochrome screen can underline them.
+const monochromeSelected = tcell.ColorWhite
+
+// monochromeScreen drops colors from every cell, backgrounds become reversed text.
+type monochromeScreen struct {
+	tcell.Screen
+}
+
+func newMonochromeScreen(screen tcell.Screen) *monochromeScreen {
+	return &monochromeScreen{Screen: screen}
+}
+
+func (s *monochromeScreen) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	s.Screen.SetContent(x, y, primary, combining, stripColors(style))
+}
+
+func (s *monochromeScreen) SetStyle(style tcell.Style) {
+	s.Screen.SetStyle(stripColors(style))
+}
+
+func (s *monochromeScreen) Fill(r rune, style tcell.Style) {
+	s.Screen.Fill(r, stripColors(style))
+}
+
+func stripColors(style tcell.Style) tcell.Style {
+	_, bg, attrs := style.Decompose()
+
+	if bg != tcell.ColorDefault {
+		attrs |= tcell.AttrReverse
+	}
+	if bg == monochromeSelected {
+		attrs |= tcell.AttrBold | tcell.AttrUnderline
+	}
+	return tcell.StyleDefault.Attributes(attrs)
+}
@@ -0,0 +1,58 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"testing"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	theme, err := NewTheme(config.ThemeConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "dark", theme.Name)
+	assert.Equal(t, "[red::]", theme.Focus.Tag())
+
+	theme, err = NewTheme(config.ThemeConfig{Name: "light", Focus: "Orange", Background: "#102030"})
+	require.NoError(t, err)
+	assert.Equal(t, "light", theme.Name)
+	assert.Equal(t, "[orange::]focus[-:-:-]", theme.Focus.Wrap("focus"))
+	assert.Equal(t, tcell.NewHexColor(0x102030), theme.Styles.PrimitiveBackgroundColor)
+
+	_, err = NewTheme(config.ThemeConfig{Name: "solarized"})
+	require.ErrorIs(t, err, errUnknownTheme)
+
+	_, err = NewTheme(config.ThemeConfig{Focus: "not-a-color"})
+	require.ErrorIs(t, err, errInvalidColor)
+}
+
+func TestNewTheme_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	theme, err := NewTheme(config.ThemeConfig{Name: "light", Focus: "red"})
+	require.NoError(t, err)
+	assert.True(t, theme.Monochrome)
+	assert.Equal(t, "[::b]", theme.Focus.Tag())
+}
+
+func TestStripColors(t *testing.T) {
+	style := stripColors(tcell.StyleDefault.Foreground(tcell.ColorRed).Background(tcell.ColorGray))
+	fg, bg, attrs := style.Decompose()
+	assert.Equal(t, tcell.ColorDefault, fg)
+	assert.Equal(t, tcell.ColorDefault, bg)
+	assert.Equal(t, tcell.AttrReverse, attrs)
+
+	style = stripColors(tcell.StyleDefault.Background(monochromeSelected))
+	_, _, attrs = style.Decompose()
+	assert.Equal(t, tcell.AttrReverse|tcell.AttrBold|tcell.AttrUnderline, attrs)
+
+	style = stripColors(tcell.StyleDefault.Foreground(tcell.ColorGreen).Bold(true))
+	_, _, attrs = style.Decompose()
+	assert.Equal(t, tcell.AttrBold, attrs)
+}
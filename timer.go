@@ -10,90 +10,299 @@ type TimerType int
 const (
 	FocusTimer TimerType = iota
 	BreakTimer
+	// StopwatchTimer counts focus up with no limit, break after it is sized to its length.
+	StopwatchTimer
 )
 
+func (t TimerType) String() string {
+	switch t {
+	case FocusTimer:
+		return "focus"
+	case BreakTimer:
+		return "break"
+	case StopwatchTimer:
+		return "stopwatch"
+	}
+	return "unknown"
+}
+
+// IsFocus reports whether timer measures focus time that is recorded as pomodoro.
+func (t TimerType) IsFocus() bool {
+	return t == FocusTimer || t == StopwatchTimer
+}
+
+// Clock tells current time. Timer takes it from the clock, so tests can move time by hand.
+type Clock interface {
+	Now() time.Time
+}
+
+// wallClock drops monotonic reading: it stops during suspend, while wall time goes on.
+type wallClock struct{}
+
+func (wallClock) Now() time.Time {
+	return time.Now().Round(0)
+}
+
+const (
+	// timerTick is how often running timer checks its deadline.
+	timerTick = 1 * time.Second
+	// suspendGap is a pause between ticks that can't be a slow tick, computer was asleep.
+	suspendGap = 10 * time.Second
+	// minTimeToFinish is the least time that can be left by adjustment.
+	minTimeToFinish = 1 * time.Second
+)
+
+// Timer counts down to absolute deadline, so dropped ticks and suspend don't make it drift.
+// Stopwatch counts up from the moment it was started instead and has no deadline.
 type Timer struct {
-	timerType    TimerType
-	mu           sync.RWMutex
+	timerType TimerType
+	mu        sync.RWMutex
+	clock     Clock
+	countUp   bool
+	// timeToFinish is remaining time while timer doesn't run.
 	timeToFinish time.Duration
-	stopSignal   chan struct{}
+	// deadline is zero while timer doesn't run.
+	deadline time.Time
+	// startedAt is the moment of last Run, elapsed is stopwatch time counted before it.
+	startedAt time.Time
+	elapsed   time.Duration
+	// asleep is time of the interval that passed while computer was suspended.
+	asleep time.Duration
+	// length is duration the interval was started with.
+	length time.Duration
+	// adjusted is time added to the interval by hand, negative if it was shortened.
+	adjusted   time.Duration
+	stopSignal chan struct{}
 }
 
 func NewFocusTimer(duration time.Duration) *Timer {
-	return &Timer{
-		timerType:    FocusTimer,
-		mu:           sync.RWMutex{},
-		timeToFinish: duration,
-		stopSignal:   make(chan struct{}),
-	}
+	return newTimer(FocusTimer, duration, wallClock{})
 }
 
 func NewBreakTimer(duration time.Duration) *Timer {
+	return newTimer(BreakTimer, duration, wallClock{})
+}
+
+func NewStopwatch() *Timer {
+	return newStopwatch(wallClock{})
+}
+
+func newStopwatch(clock Clock) *Timer {
+	timer := newTimer(StopwatchTimer, 0, clock)
+	timer.countUp = true
+	return timer
+}
+
+func newTimer(timerType TimerType, duration time.Duration, clock Clock) *Timer {
 	return &Timer{
-		timerType:    BreakTimer,
+		timerType:    timerType,
 		mu:           sync.RWMutex{},
+		clock:        clock,
+		countUp:      false,
 		timeToFinish: duration,
-		stopSignal:   make(chan struct{}),
+		deadline:     time.Time{},
+		startedAt:    time.Time{},
+		elapsed:      0,
+		asleep:       0,
+		length:       duration,
+		adjusted:     0,
+		stopSignal:   nil,
 	}
 }
 
+// Stop freezes remaining time and stops goroutine of Run.
 func (t *Timer) Stop() {
-	select {
-	case t.stopSignal <- struct{}{}:
-	default:
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stop()
+}
+
+// stop must be called with mu locked.
+func (t *Timer) stop() {
+	if t.stopSignal == nil {
+		return
+	}
+	if t.countUp {
+		t.elapsed += t.clock.Now().Sub(t.startedAt)
 	}
+	t.timeToFinish = t.remaining()
+	t.deadline = time.Time{}
+	close(t.stopSignal)
+	t.stopSignal = nil
 }
 
 func (t *Timer) TimeToFinish() time.Duration {
 	t.mu.RLock()
-	duration := t.timeToFinish
-	t.mu.RUnlock()
-	return duration
+	defer t.mu.RUnlock()
+	return t.remaining()
+}
+
+// remaining rounds time up to whole seconds, the same way clock shows it. Must be called with mu locked.
+func (t *Timer) remaining() time.Duration {
+	if t.deadline.IsZero() {
+		return t.timeToFinish
+	}
+	left := t.deadline.Sub(t.clock.Now())
+	if left <= 0 {
+		return 0
+	}
+	return (left + time.Second - 1).Truncate(time.Second)
+}
+
+// Deadline returns moment when running timer finishes, zero if timer doesn't run.
+func (t *Timer) Deadline() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.deadline
+}
+
+// Elapsed returns time counted by stopwatch, zero for countdown timer.
+func (t *Timer) Elapsed() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if !t.countUp {
+		return 0
+	}
+	elapsed := t.elapsed
+	if t.stopSignal != nil {
+		elapsed += t.clock.Now().Sub(t.startedAt)
+	}
+	return elapsed.Truncate(time.Second)
+}
+
+// Asleep returns how long computer was suspended during the interval.
+func (t *Timer) Asleep() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.asleep
 }
 
 func (t *Timer) Reset(duration time.Duration) {
 	t.mu.Lock()
+	t.stop()
 	t.timeToFinish = duration
+	t.length = duration
+	t.elapsed = 0
+	t.asleep = 0
+	t.adjusted = 0
 	t.mu.Unlock()
 }
 
+// Adjust adds d to remaining time, negative d takes it away. Timer keeps at least
+// minTimeToFinish, so shortening doesn't finish it. Timer in overtime has already reached
+// its deadline and isn't adjusted. Returns change that was applied.
+func (t *Timer) Adjust(d time.Duration) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.deadline.IsZero() && t.clock.Now().After(t.deadline) {
+		return 0
+	}
+
+	left := t.remaining()
+	if d < 0 && left+d < minTimeToFinish {
+		d = min(minTimeToFinish-left, 0)
+	}
+
+	if t.deadline.IsZero() {
+		t.timeToFinish += d
+	} else {
+		t.deadline = t.deadline.Add(d)
+	}
+	t.adjusted += d
+	return d
+}
+
+// Overtime returns how long running timer counts past its deadline.
+func (t *Timer) Overtime() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.deadline.IsZero() {
+		return 0
+	}
+	return max(t.clock.Now().Sub(t.deadline), 0)
+}
+
+// Adjusted returns time added to the interval by hand.
+func (t *Timer) Adjusted() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.adjusted
+}
+
+// Length returns full length of the interval: duration it was started with and time added by hand.
+func (t *Timer) Length() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.length + t.adjusted
+}
+
+// Run starts countdown. Returned channel gets a value when deadline is reached
+// and is closed when timer stops.
 func (t *Timer) Run() chan struct{} {
 	doneChan := make(chan struct{})
 
-	const timeToStop = 0 * time.Second
-	go t.run(doneChan, timeToStop)
+	t.mu.Lock()
+	t.stop()
+	startedAt := t.clock.Now()
+	t.startedAt = startedAt
+	if !t.countUp {
+		t.deadline = startedAt.Add(t.timeToFinish)
+	}
+	t.stopSignal = make(chan struct{})
+	stopSignal := t.stopSignal
+	t.mu.Unlock()
+
+	go t.run(doneChan, stopSignal, startedAt)
 
 	return doneChan
 }
 
-func (t *Timer) run(doneChan chan struct{}, timeToStop time.Duration) {
+func (t *Timer) run(doneChan chan struct{}, stopSignal chan struct{}, lastTick time.Time) {
 	defer close(doneChan)
 
-	const timerDelta = 1 * time.Second
-	tick := time.NewTicker(timerDelta)
+	tick := time.NewTicker(timerTick)
 	defer tick.Stop()
 
 	for {
 		select {
 		case <-tick.C:
-			t.tick(timerDelta)
+			now := t.clock.Now()
+			if !t.check(lastTick, now) {
+				lastTick = now
+				continue
+			}
 
-			if t.TimeToFinish() <= timeToStop {
-				doneChan <- struct{}{}
-				return
+			select {
+			case doneChan <- struct{}{}:
+			case <-stopSignal:
 			}
-		case <-t.stopSignal:
+			return
+		case <-stopSignal:
 			return
 		}
 	}
 }
 
-func (t *Timer) tick(duration time.Duration) {
+// check notes suspend between ticks and reports whether deadline is reached.
+func (t *Timer) check(lastTick, now time.Time) bool {
 	t.mu.Lock()
-	if t.timeToFinish > duration {
-		t.timeToFinish -= duration
-	} else {
-		t.timeToFinish = 0 * time.Second
+	defer t.mu.Unlock()
+
+	if t.stopSignal == nil {
+		return false
 	}
-	t.mu.Unlock()
+	if now.Sub(lastTick) > suspendGap {
+		// wall clock jumped, count sleep only up to the deadline
+		wakeUp := now
+		if !t.countUp && wakeUp.After(t.deadline) {
+			wakeUp = t.deadline
+		}
+		if slept := wakeUp.Sub(lastTick); slept > 0 {
+			t.asleep += slept
+		}
+	}
+	return !t.countUp && !now.Before(t.deadline)
 }
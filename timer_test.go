@@ -1,6 +1,7 @@
 package main
 
 import (
+	"sync"
 	"testing"
 	"time"
 
@@ -65,11 +66,123 @@ func TestTimer_Reset(t *testing.T) {
 	assert.Equal(t, 5*time.Second, timer.timeToFinish)
 }
 
-func TestTimer_Tick(t *testing.T) {
-	timer := NewFocusTimer(5 * time.Second)
-	timer.tick(1 * time.Second)
-	assert.Equal(t, 4*time.Second, timer.timeToFinish)
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestTimer_Deadline(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)}
+	timer := newTimer(FocusTimer, 5*time.Minute, clock)
+	assert.True(t, timer.Deadline().IsZero())
+
+	timer.Run()
+	assert.Equal(t, clock.Now().Add(5*time.Minute), timer.Deadline())
+
+	// time is derived from deadline, not counted by ticks
+	clock.Add(90 * time.Second)
+	assert.Equal(t, 210*time.Second, timer.TimeToFinish())
+
+	// part of second left is shown as whole second
+	clock.Add(500 * time.Millisecond)
+	assert.Equal(t, 210*time.Second, timer.TimeToFinish())
+
+	timer.Stop()
+	assert.True(t, timer.Deadline().IsZero())
+	clock.Add(time.Minute)
+	assert.Equal(t, 210*time.Second, timer.TimeToFinish())
+}
+
+func TestTimer_Suspend(t *testing.T) {
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)
+	clock := &fakeClock{now: start}
+	timer := newTimer(FocusTimer, 25*time.Minute, clock)
+	doneChan := timer.Run()
+
+	// computer sleeps for an hour, timer ran out 50 minutes ago
+	clock.Add(10*time.Minute + time.Hour)
+
+	select {
+	case <-doneChan:
+		// OK
+	case <-time.After(2 * timerTick):
+		t.Fatal("Expected timer to finish after suspend, but it did not")
+	}
+	assert.Equal(t, start.Add(25*time.Minute), timer.Deadline())
+	assert.Equal(t, 25*time.Minute, timer.Asleep())
+	assert.Equal(t, 0*time.Second, timer.TimeToFinish())
+
+	timer.Reset(25 * time.Minute)
+	assert.Equal(t, 0*time.Second, timer.Asleep())
+}
+
+func TestTimer_Adjust(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)}
+	timer := newTimer(FocusTimer, 25*time.Minute, clock)
+
+	// paused timer
+	assert.Equal(t, 5*time.Minute, timer.Adjust(5*time.Minute))
+	assert.Equal(t, 30*time.Minute, timer.TimeToFinish())
+
+	// running timer moves its deadline
+	timer.Run()
+	clock.Add(28 * time.Minute)
+	assert.Equal(t, -time.Minute, timer.Adjust(-time.Minute))
+	assert.Equal(t, time.Minute, timer.TimeToFinish())
+
+	// adjustment doesn't finish timer
+	assert.Equal(t, -59*time.Second, timer.Adjust(-time.Minute))
+	assert.Equal(t, minTimeToFinish, timer.TimeToFinish())
+	assert.Equal(t, 5*time.Minute-time.Minute-59*time.Second, timer.Adjusted())
+	assert.Equal(t, 25*time.Minute+timer.Adjusted(), timer.Length())
+
+	// timer in overtime isn't adjusted
+	clock.Add(2 * time.Second)
+	assert.Equal(t, time.Duration(0), timer.Adjust(5*time.Minute))
+	assert.Equal(t, time.Second, timer.Overtime())
+
+	timer.Reset(time.Minute)
+	assert.Equal(t, 0*time.Second, timer.Adjusted())
+	assert.Equal(t, time.Minute, timer.Length())
+}
+
+func TestStopwatch(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.October, 7, 10, 0, 0, 0, time.Local)}
+	stopwatch := newStopwatch(clock)
+	doneChan := stopwatch.Run()
+
+	clock.Add(40 * time.Minute)
+	assert.Equal(t, 40*time.Minute, stopwatch.Elapsed())
+	assert.Equal(t, 0*time.Second, stopwatch.Overtime())
+
+	// pause keeps counted time
+	stopwatch.Stop()
+	clock.Add(time.Hour)
+	assert.Equal(t, 40*time.Minute, stopwatch.Elapsed())
+
+	select {
+	case _, ok := <-doneChan:
+		assert.False(t, ok, "stopwatch must not finish by itself")
+	case <-time.After(2 * timerTick):
+		t.Fatal("Expected stopwatch goroutine to stop, but it did not")
+	}
+
+	stopwatch.Run()
+	clock.Add(5*time.Minute + 500*time.Millisecond)
+	assert.Equal(t, 45*time.Minute, stopwatch.Elapsed())
 
-	timer.tick(5 * time.Second)
-	assert.Equal(t, 0*time.Second, timer.timeToFinish)
+	stopwatch.Reset(0)
+	assert.Equal(t, 0*time.Second, stopwatch.Elapsed())
 }
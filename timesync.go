@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+)
+
+const (
+	syncTimeout = 30 * time.Second
+	// syncCreatedWith names the app in entries of services that ask for it.
+	syncCreatedWith = "pomotrack"
+)
+
+var (
+	errSyncDisabled = errors.New("sync isn't configured")
+	errSyncRequest  = errors.New("time tracking service refused entry")
+)
+
+// timeEntry is pomodoro as it's sent to time tracking service. Pauses aren't billed:
+// entry starts with pomodoro and lasts as long as it was running.
+type timeEntry struct {
+	Description string
+	Project     string
+	Start       time.Time
+	Stop        time.Time
+}
+
+// entryPusher creates time entry in service and returns its ID there.
+type entryPusher interface {
+	push(ctx context.Context, entry timeEntry) (string, error)
+}
+
+// TimeSync pushes finished pomodoros to time tracking service. Pushed pomodoros are recorded
+// in database, so every pomodoro is sent once even if sync is run many times.
+type TimeSync struct {
+	logger  *slog.Logger
+	storage *Storage
+	cfg     config.SyncConfig
+	pusher  entryPusher
+	mu      sync.Mutex
+}
+
+func NewTimeSync(logger *slog.Logger, storage *Storage, cfg config.SyncConfig) (*TimeSync, error) {
+	client := &http.Client{Timeout: syncTimeout} //nolint:exhaustruct // defaults are fine
+
+	var pusher entryPusher
+	switch cfg.Service {
+	case config.SyncToggl:
+		workspace, err := strconv.Atoi(cfg.Workspace)
+		if err != nil {
+			return nil, fmt.Errorf("toggl workspace must be a number: %w", err)
+		}
+		pusher = &togglPusher{client: client, baseURL: cfg.BaseURL, token: cfg.Token, workspace: workspace}
+	case config.SyncClockify:
+		pusher = &clockifyPusher{client: client, baseURL: cfg.BaseURL, token: cfg.Token, workspace: cfg.Workspace}
+	default:
+		return nil, errSyncDisabled
+	}
+
+	return &TimeSync{
+		logger:  logger,
+		storage: storage,
+		cfg:     cfg,
+		pusher:  pusher,
+		mu:      sync.Mutex{},
+	}, nil
+}
+
+// Sync pushes pomodoros that aren't synced yet and returns how many were sent.
+// It stops at the first failure, the rest is sent next time.
+func (ts *TimeSync) Sync(ctx context.Context) (int, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	pomodoros, err := ts.storage.UnsyncedPomodoros(ts.cfg.Service)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, pomodoro := range pomodoros {
+		id, err := ts.pusher.push(ctx, ts.entry(pomodoro))
+		if err != nil {
+			return i, fmt.Errorf("can't sync pomodoro %d: %w", pomodoro.ID, err)
+		}
+		if err = ts.storage.MarkSynced(pomodoro.ID, ts.cfg.Service, id); err != nil {
+			return i, err
+		}
+	}
+	return len(pomodoros), nil
+}
+
+// SyncInBackground runs sync after pomodoro, failures are only logged.
+func (ts *TimeSync) SyncInBackground() {
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	count, err := ts.Sync(ctx)
+	if err != nil {
+		ts.logger.Error("can't sync pomodoros", slog.Any("error", err))
+		return
+	}
+	ts.logger.Info("pomodoros synced", slog.Int("count", count), slog.String("service", ts.cfg.Service))
+}
+
+func (ts *TimeSync) entry(pomodoro *Pomodoro) timeEntry {
+	return timeEntry{
+		Description: sessionSummary(pomodoro),
+		Project:     ts.cfg.ProjectFor(pomodoro.Task),
+		Start:       pomodoro.StartAt,
+		Stop:        pomodoro.StartAt.Add(time.Duration(pomodoro.SecondsDuration) * time.Second),
+	}
+}
+
+// togglPusher creates entries with Toggl Track API v9.
+type togglPusher struct {
+	client    *http.Client
+	baseURL   string
+	token     string
+	workspace int
+}
+
+func (p *togglPusher) push(ctx context.Context, entry timeEntry) (string, error) {
+	body := map[string]any{
+		"created_with": syncCreatedWith,
+		"description":  entry.Description,
+		"start":        entry.Start.UTC().Format(time.RFC3339),
+		"stop":         entry.Stop.UTC().Format(time.RFC3339),
+		"duration":     int(entry.Stop.Sub(entry.Start).Seconds()),
+		"workspace_id": p.workspace,
+		"tags":         []string{syncCreatedWith},
+	}
+	if entry.Project != "" {
+		project, err := strconv.Atoi(entry.Project)
+		if err != nil {
+			return "", fmt.Errorf("toggl project must be a number: %w", err)
+		}
+		body["project_id"] = project
+	}
+
+	url := fmt.Sprintf("%s/workspaces/%d/time_entries", p.baseURL, p.workspace)
+	return postTimeEntry(ctx, p.client, url, body, func(r *http.Request) {
+		r.SetBasicAuth(p.token, "api_token")
+	})
+}
+
+// clockifyPusher creates entries with Clockify API v1.
+type clockifyPusher struct {
+	client    *http.Client
+	baseURL   string
+	token     string
+	workspace string
+}
+
+func (p *clockifyPusher) push(ctx context.Context, entry timeEntry) (string, error) {
+	body := map[string]any{
+		"description": entry.Description,
+		"start":       entry.Start.UTC().Format(time.RFC3339),
+		"end":         entry.Stop.UTC().Format(time.RFC3339),
+	}
+	if entry.Project != "" {
+		body["projectId"] = entry.Project
+	}
+
+	url := fmt.Sprintf("%s/workspaces/%s/time-entries", p.baseURL, p.workspace)
+	return postTimeEntry(ctx, p.client, url, body, func(r *http.Request) {
+		r.Header.Set("X-Api-Key", p.token)
+	})
+}
+
+// postTimeEntry sends entry as JSON and returns "id" field of response, number or string.
+func postTimeEntry(ctx context.Context, client *http.Client, url string, body any,
+	auth func(*http.Request)) (string, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return "", fmt.Errorf("can't encode time entry: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("can't create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	auth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("can't send time entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%w: %s: %s", errSyncRequest, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var created struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", fmt.Errorf("can't decode created entry: %w", err)
+	}
+	return strings.Trim(string(created.ID), `"`), nil
+}
+
+// runSync handles "pomotrack sync": pushes pomodoros that aren't synced yet.
+func runSync(logger *slog.Logger, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("can't load config: %w", err)
+	}
+
+	storage, err := openStorage(logger)
+	if err != nil {
+		return err
+	}
+	defer storage.DB.Close()
+
+	timeSync, err := NewTimeSync(logger, storage, cfg.Sync)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	count, err := timeSync.Sync(ctx)
+	fmt.Printf("synced %d pomodoros to %s\n", count, cfg.Sync.Service)
+	return err
+}
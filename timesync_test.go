@@ -0,0 +1,153 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arevbond/PomoTrack/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTracker records time entries posted to it and answers with given id.
+type fakeTracker struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	entries  []map[string]any
+	id       string
+}
+
+func (f *fakeTracker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var entry map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	f.requests = append(f.requests, r)
+	f.entries = append(f.entries, entry)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(`{"id":` + f.id + `}`))
+}
+
+func createSyncPomodoros(t *testing.T, start time.Time) {
+	t.Helper()
+	sessions := []*Pomodoro{
+		{StartAt: start, FinishAt: start.Add(25 * time.Minute), SecondsDuration: 25 * 60, Task: "Write report"},
+		{StartAt: start.Add(time.Hour), FinishAt: start.Add(time.Hour + 10*time.Minute), SecondsDuration: 10 * 60,
+			Outcome: OutcomeAbandoned},
+		{StartAt: start.Add(2 * time.Hour), FinishAt: start.Add(2*time.Hour + 5*time.Minute), SecondsDuration: 5 * 60,
+			Kind: SessionBreak},
+	}
+	for _, session := range sessions {
+		require.NoError(t, s.CreatePomodoro(session))
+	}
+}
+
+func TestTimeSync_Toggl(t *testing.T) {
+	defer clearTable()
+	tracker := &fakeTracker{id: "4242"}
+	server := httptest.NewServer(tracker)
+	defer server.Close()
+
+	start := time.Date(2024, time.October, 7, 10, 0, 0, 0, time.UTC)
+	createSyncPomodoros(t, start)
+
+	timeSync, err := NewTimeSync(slog.Default(), s, config.SyncConfig{
+		Service:   config.SyncToggl,
+		BaseURL:   server.URL,
+		Token:     "secret",
+		Workspace: "17",
+		Projects:  map[string]string{"Write report": "99"},
+	})
+	require.NoError(t, err)
+
+	count, err := timeSync.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.Len(t, tracker.requests, 1)
+	assert.Equal(t, "/workspaces/17/time_entries", tracker.requests[0].URL.Path)
+	user, password, ok := tracker.requests[0].BasicAuth()
+	assert.True(t, ok)
+	assert.Equal(t, "secret", user)
+	assert.Equal(t, "api_token", password)
+
+	entry := tracker.entries[0]
+	assert.Equal(t, "Write report", entry["description"])
+	assert.Equal(t, "2024-10-07T10:00:00Z", entry["start"])
+	assert.Equal(t, "2024-10-07T10:25:00Z", entry["stop"])
+	assert.InDelta(t, 1500, entry["duration"], 0)
+	assert.InDelta(t, 17, entry["workspace_id"], 0)
+	assert.InDelta(t, 99, entry["project_id"], 0)
+
+	count, err = timeSync.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Len(t, tracker.requests, 1)
+}
+
+func TestTimeSync_Clockify(t *testing.T) {
+	defer clearTable()
+	tracker := &fakeTracker{id: `"5f1d"`}
+	server := httptest.NewServer(tracker)
+	defer server.Close()
+
+	createSyncPomodoros(t, time.Date(2024, time.October, 7, 10, 0, 0, 0, time.UTC))
+
+	timeSync, err := NewTimeSync(slog.Default(), s, config.SyncConfig{
+		Service:   config.SyncClockify,
+		BaseURL:   server.URL,
+		Token:     "secret",
+		Workspace: "ws1",
+	})
+	require.NoError(t, err)
+
+	count, err := timeSync.Sync(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	require.Len(t, tracker.requests, 1)
+	assert.Equal(t, "/workspaces/ws1/time-entries", tracker.requests[0].URL.Path)
+	assert.Equal(t, "secret", tracker.requests[0].Header.Get("X-Api-Key"))
+	assert.Equal(t, "2024-10-07T10:25:00Z", tracker.entries[0]["end"])
+	assert.NotContains(t, tracker.entries[0], "projectId")
+
+	pending, err := s.UnsyncedPomodoros(config.SyncClockify)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+}
+
+func TestTimeSync_Failure(t *testing.T) {
+	defer clearTable()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "invalid token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	createSyncPomodoros(t, time.Date(2024, time.October, 7, 10, 0, 0, 0, time.UTC))
+
+	timeSync, err := NewTimeSync(slog.Default(), s, config.SyncConfig{
+		Service:   config.SyncClockify,
+		BaseURL:   server.URL,
+		Workspace: "ws1",
+	})
+	require.NoError(t, err)
+
+	count, err := timeSync.Sync(context.Background())
+	require.ErrorIs(t, err, errSyncRequest)
+	assert.Equal(t, 0, count)
+
+	pending, err := s.UnsyncedPomodoros(config.SyncClockify)
+	require.NoError(t, err)
+	assert.Len(t, pending, 1)
+}
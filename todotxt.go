@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// todoPomKey keeps progress of task, e.g. "pom:2/4" is 2 of 4 pomodoros done.
+	todoPomKey = "pom"
+	// todoActiveKey marks task pomodoros are counted to.
+	todoActiveKey = "pomactive"
+	// todoPriorityKey keeps priority of completed task, todo.txt has no priority for them.
+	todoPriorityKey = "pri"
+	todoDateFormat  = "2006-01-02"
+)
+
+// todoTxtFormat keeps tasks in todo.txt file. Progress is kept in "pom:2/4" extension
+// and active task is marked with "pomactive:1", other content of line stays as it is.
+type todoTxtFormat struct{}
+
+func NewTodoTxtTasks(path string) *FileTasks {
+	return NewFileTasks(path, todoTxtFormat{})
+}
+
+func (todoTxtFormat) parse(raw string) (*Task, bool) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, false
+	}
+	line := parseTodoLine(raw)
+	return line.task(), true
+}
+
+func (todoTxtFormat) update(raw string, task *Task) string {
+	line := parseTodoLine(raw)
+	line.update(task)
+	return line.String()
+}
+
+func (todoTxtFormat) format(task *Task) string {
+	line := todoLine{
+		raw:      "",
+		done:     false,
+		doneDate: "",
+		priority: "",
+		created:  "",
+		words:    strings.Fields(task.Name),
+		changed:  true,
+	}
+	if !task.CreateAt.IsZero() {
+		line.created = task.CreateAt.Format(todoDateFormat)
+	}
+	line.update(task)
+	return line.String()
+}
+
+// todoLine is one task of todo.txt:
+// "x 2024-10-07 (A) 2024-10-01 Write report +work @desk pom:2/4".
+type todoLine struct {
+	raw      string
+	done     bool
+	doneDate string
+	priority string
+	created  string
+	// words are description with projects, contexts and key:value extensions.
+	words   []string
+	changed bool
+}
+
+func parseTodoLine(raw string) todoLine {
+	line := todoLine{raw: raw, done: false, doneDate: "", priority: "", created: "", words: nil, changed: false}
+
+	fields := strings.Fields(raw)
+	if len(fields) > 0 && fields[0] == "x" {
+		line.done = true
+		fields = fields[1:]
+		if len(fields) > 0 && isTodoDate(fields[0]) {
+			line.doneDate, fields = fields[0], fields[1:]
+		}
+	}
+	if len(fields) > 0 && isTodoPriority(fields[0]) {
+		line.priority, fields = fields[0], fields[1:]
+	}
+	if len(fields) > 0 && isTodoDate(fields[0]) {
+		line.created, fields = fields[0], fields[1:]
+	}
+	line.words = fields
+	return line
+}
+
+func isTodoDate(s string) bool {
+	_, err := time.Parse(todoDateFormat, s)
+	return err == nil
+}
+
+func isTodoPriority(s string) bool {
+	return len(s) == 3 && s[0] == '(' && s[1] >= 'A' && s[1] <= 'Z' && s[2] == ')'
+}
+
+func (l *todoLine) extension(key string) (string, bool) {
+	for _, word := range l.words {
+		if value, ok := strings.CutPrefix(word, key+":"); ok {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// setExtension replaces value of extension or adds it to the end, empty value removes it.
+func (l *todoLine) setExtension(key, value string) {
+	words := make([]string, 0, len(l.words)+1)
+	found := false
+	for _, word := range l.words {
+		if !strings.HasPrefix(word, key+":") {
+			words = append(words, word)
+			continue
+		}
+		if value != "" && !found {
+			words = append(words, key+":"+value)
+		}
+		found = true
+	}
+	if value != "" && !found {
+		words = append(words, key+":"+value)
+	}
+	l.words = words
+}
+
+// name is description without extensions of PomoTrack.
+func (l *todoLine) name() string {
+	words := make([]string, 0, len(l.words))
+	for _, word := range l.words {
+		if !isPomoTrackExtension(word) {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isPomoTrackExtension(word string) bool {
+	return strings.HasPrefix(word, todoPomKey+":") || strings.HasPrefix(word, todoActiveKey+":")
+}
+
+func (l *todoLine) task() *Task {
+	var completed, required int
+	if value, ok := l.extension(todoPomKey); ok {
+		done, estimate, _ := strings.Cut(value, "/")
+		completed, _ = strconv.Atoi(done)
+		required, _ = strconv.Atoi(estimate)
+	}
+	_, active := l.extension(todoActiveKey)
+	created, _ := time.ParseInLocation(todoDateFormat, l.created, time.Local)
+
+	return &Task{
+		ID:                 0,
+		Name:               l.name(),
+		PomodorosRequired:  required,
+		PomodorosCompleted: completed,
+		IsComplete:         l.done,
+		IsActive:           active,
+		CreateAt:           created,
+		UUID:               "",
+		CompleteAt:         time.Time{},
+		line:               "",
+	}
+}
+
+// update writes task fields to line, other content of line is kept.
+// Line is rebuilt only if its fields are changed, so spacing of other lines is untouched.
+func (l *todoLine) update(task *Task) {
+	before := l.build()
+
+	if task.Name != l.name() {
+		words := strings.Fields(task.Name)
+		for _, word := range l.words {
+			if isPomoTrackExtension(word) {
+				words = append(words, word)
+			}
+		}
+		l.words = words
+	}
+
+	if task.IsComplete != l.done {
+		l.done = task.IsComplete
+		if l.done {
+			l.doneDate = time.Now().Format(todoDateFormat)
+			if l.priority != "" {
+				l.setExtension(todoPriorityKey, l.priority[1:2])
+				l.priority = ""
+			}
+		} else {
+			l.doneDate = ""
+			if priority, ok := l.extension(todoPriorityKey); ok && len(priority) == 1 {
+				l.priority = "(" + priority + ")"
+				l.setExtension(todoPriorityKey, "")
+			}
+		}
+	}
+
+	_, hasPom := l.extension(todoPomKey)
+	if hasPom || task.PomodorosRequired > 0 || task.PomodorosCompleted > 0 {
+		l.setExtension(todoPomKey, fmt.Sprintf("%d/%d", task.PomodorosCompleted, task.PomodorosRequired))
+	}
+	if task.IsActive {
+		l.setExtension(todoActiveKey, "1")
+	} else {
+		l.setExtension(todoActiveKey, "")
+	}
+
+	if l.build() != before {
+		l.changed = true
+	}
+}
+
+func (l todoLine) String() string {
+	if !l.changed {
+		return l.raw
+	}
+	return l.build()
+}
+
+func (l *todoLine) build() string {
+	parts := make([]string, 0, len(l.words)+4)
+	if l.done {
+		parts = append(parts, "x")
+		if l.doneDate != "" {
+			parts = append(parts, l.doneDate)
+		}
+	}
+	if l.priority != "" {
+		parts = append(parts, l.priority)
+	}
+	if l.created != "" {
+		parts = append(parts, l.created)
+	}
+	parts = append(parts, l.words...)
+	return strings.Join(parts, " ")
+}
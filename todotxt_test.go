@@ -0,0 +1,163 @@
+//nolint:exhaustruct // test data
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const todoTxtContent = `(A) 2024-10-01 Write report +work @desk pom:1/3 pomactive:1 due:2024-10-10
+
+x 2024-10-02 2024-09-30 Call   bank @phone
+Read book +fun
+`
+
+func newTestTodoTxt(t *testing.T, content string) (*FileTasks, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return NewTodoTxtTasks(path), path
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestTodoTxtTasks_Tasks(t *testing.T) {
+	tasks, _ := newTestTodoTxt(t, todoTxtContent)
+
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, &Task{
+		ID:                 1,
+		Name:               "Write report +work @desk due:2024-10-10",
+		PomodorosRequired:  3,
+		PomodorosCompleted: 1,
+		IsComplete:         false,
+		IsActive:           true,
+		CreateAt:           time.Date(2024, time.October, 1, 0, 0, 0, 0, time.Local),
+		line:               "(A) 2024-10-01 Write report +work @desk pom:1/3 pomactive:1 due:2024-10-10",
+	}, list[0])
+	assert.Equal(t, 3, list[1].ID)
+	assert.True(t, list[1].IsComplete)
+	assert.Equal(t, "Call bank @phone", list[1].Name)
+	assert.Equal(t, 0, list[2].PomodorosRequired)
+
+	name, err := tasks.ActiveTaskName()
+	require.NoError(t, err)
+	assert.Equal(t, "Write report +work @desk due:2024-10-10", name)
+}
+
+func TestTodoTxtTasks_IncPomodoroActiveTask(t *testing.T) {
+	tasks, path := newTestTodoTxt(t, todoTxtContent)
+
+	require.NoError(t, tasks.IncPomodoroActiveTask())
+	assert.Equal(t, `(A) 2024-10-01 Write report +work @desk pom:2/3 pomactive:1 due:2024-10-10
+
+x 2024-10-02 2024-09-30 Call   bank @phone
+Read book +fun
+`, readTestFile(t, path))
+
+	require.NoError(t, tasks.IncPomodoroActiveTask())
+	today := time.Now().Format(todoDateFormat)
+	assert.Equal(t, "x "+today+` 2024-10-01 Write report +work @desk pom:3/3 pomactive:1 due:2024-10-10 pri:A
+
+x 2024-10-02 2024-09-30 Call   bank @phone
+Read book +fun
+`, readTestFile(t, path))
+
+	active, err := tasks.ActiveTask()
+	require.NoError(t, err)
+	active.IsComplete = false
+	require.NoError(t, tasks.UpdateTask(active))
+	assert.Equal(t, `(A) 2024-10-01 Write report +work @desk pom:3/3 pomactive:1 due:2024-10-10
+
+x 2024-10-02 2024-09-30 Call   bank @phone
+Read book +fun
+`, readTestFile(t, path))
+}
+
+func TestTodoTxtTasks_ChangeActive(t *testing.T) {
+	tasks, path := newTestTodoTxt(t, todoTxtContent)
+
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+
+	list[0].IsActive = false
+	require.NoError(t, tasks.UpdateTask(list[0]))
+	list[2].IsActive = true
+	require.NoError(t, tasks.UpdateTask(list[2]))
+
+	assert.Equal(t, `(A) 2024-10-01 Write report +work @desk pom:1/3 due:2024-10-10
+
+x 2024-10-02 2024-09-30 Call   bank @phone
+Read book +fun pomactive:1
+`, readTestFile(t, path))
+
+	// pomodoro on task without estimate doesn't complete it
+	require.NoError(t, tasks.IncPomodoroActiveTask())
+	active, err := tasks.ActiveTask()
+	require.NoError(t, err)
+	assert.Equal(t, 1, active.PomodorosCompleted)
+	assert.False(t, active.IsComplete)
+}
+
+func TestTodoTxtTasks_CreateDelete(t *testing.T) {
+	tasks, path := newTestTodoTxt(t, "Read book\r\n")
+
+	task := &Task{Name: "Plan week", PomodorosRequired: 2,
+		CreateAt: time.Date(2024, time.October, 7, 9, 0, 0, 0, time.Local)}
+	require.NoError(t, tasks.CreateTask(task))
+	assert.Equal(t, 2, task.ID)
+	assert.Equal(t, "Read book\r\n2024-10-07 Plan week pom:0/2\r\n", readTestFile(t, path))
+
+	require.NoError(t, tasks.DeleteTask(&Task{ID: 1}))
+	assert.Equal(t, "2024-10-07 Plan week pom:0/2\r\n", readTestFile(t, path))
+	require.ErrorIs(t, tasks.DeleteTask(&Task{ID: 5}), errTaskNotFound)
+
+	_, err := tasks.ActiveTask()
+	require.ErrorIs(t, err, errNoActiveTask)
+}
+
+func TestTodoTxtTasks_MissingFile(t *testing.T) {
+	tasks := NewTodoTxtTasks(filepath.Join(t.TempDir(), "todo.txt"))
+
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+	assert.Empty(t, list)
+
+	require.NoError(t, tasks.CreateTask(&Task{Name: "First", IsActive: true}))
+	list, err = tasks.Tasks()
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.True(t, list[0].IsActive)
+}
+
+func TestTodoTxtTasks_EditedElsewhere(t *testing.T) {
+	tasks, path := newTestTodoTxt(t, "Write report\nRead book\nCall bank\n")
+
+	list, err := tasks.Tasks()
+	require.NoError(t, err)
+
+	// another program adds line above and changes "Call bank" after the list was read
+	require.NoError(t, os.WriteFile(path, []byte("Plan week\nWrite report\nRead book\nCall bank today\n"), 0o600))
+
+	list[1].IsActive = true
+	require.NoError(t, tasks.UpdateTask(list[1]))
+	assert.Equal(t, 3, list[1].ID)
+	require.ErrorIs(t, tasks.UpdateTask(list[2]), errTaskNotFound)
+	require.ErrorIs(t, tasks.DeleteTask(list[2]), errTaskNotFound)
+	require.NoError(t, tasks.DeleteTask(list[0]))
+
+	assert.Equal(t, "Plan week\nRead book pomactive:1\nCall bank today\n", readTestFile(t, path))
+}
@@ -15,21 +15,34 @@ type pomodoroTracker interface {
 	HandlePomodoroStateChanges()
 	TodayPomodoros() ([]*Pomodoro, error)
 	Pomodoros() ([]*Pomodoro, error)
+	PomodorosBetween(from, to time.Time) ([]*Pomodoro, error)
 	RemovePomodoro(id int) error
 	CreateNewPomodoro(startAt time.Time, finishAt time.Time, duration int) (*Pomodoro, error)
 	Hours([]*Pomodoro) float64
 	CountDays([]*Pomodoro) int
 	HoursInWeek([]*Pomodoro) [7]int
-	FinishRunningPomodoro()
+	OvertimeHours([]*Pomodoro) float64
+	Breaks() ([]*Pomodoro, error)
+	CompletionRate([]*Pomodoro) float64
+	HoursByRepo([]*Pomodoro) []RepoHours
+	HoursByProfile([]*Pomodoro) []ProfileHours
+	FinishRunningPomodoro(minFocus time.Duration)
+	SetPauseLimit(limit config.PausesConfig)
+	SetDiscardShort(discard bool)
+	PauseLimit() config.PausesConfig
+	SubtractFromPomodoro(id int, d time.Duration) error
+	OnSuspend(f func(SuspendEvent))
+	ExportCalendar() (string, error)
 }
 
 type taskManager interface {
 	Tasks() ([]*Task, error)
 	CreateTask(task *Task) error
-	DeleteTask(id int) error
+	DeleteTask(task *Task) error
 	ActiveTask() (*Task, error)
 	UpdateTask(*Task) error
 	IncPomodoroActiveTask() error
+	ActiveTaskName() (string, error)
 }
 
 type UIManager struct {
@@ -39,40 +52,99 @@ type UIManager struct {
 
 	stateManager *StateManager
 	stateUpdates chan StateEvent
+	plans        *PlanRunner
 
 	pomodoroTracker      pomodoroTracker
 	statePomodoroUpdates chan StateEvent
+	// stateListeners get every state event after PomodoroManager, unlike events subscribers
+	stateListeners []chan<- StateEvent
 
 	taskTracker taskManager
 
+	config *config.Config
+	events *EventBroker
+	keys   *KeyBindings
+	theme  *Theme
+
 	allowedTransitions map[PageName][]PageName
-	keyPageMapping     map[tcell.Key]*Page
+	keyPageMapping     map[Action]*Page
+
+	currentPage *Page
+	bottomPanel *tview.TextView
+	showKeyTips bool
 }
 
 type StateEvent struct {
 	TimerType TimerType
 	NewState  TimerState
+	// Profile is the name of timer profile active at the moment of change.
+	Profile string
+	// At is the moment of change, finish after suspend happened earlier than event is sent.
+	At time.Time
+	// Asleep is time finished interval spent in suspend.
+	Asleep time.Duration
+	// Adjusted is time added to the interval by hand before the change.
+	Adjusted time.Duration
+	// Overtime is time focus counted past zero in flow mode.
+	Overtime time.Duration
+	// Skipped is set when interval was finished before its time ran out.
+	Skipped bool
+	// Abandoned is set instead of Skipped for focus stopped early in strict mode.
+	Abandoned bool
+	// TooShort is set for focus shorter than configured minimum, it doesn't count as pomodoro.
+	TooShort bool
 }
 
-func NewUIManager(l *slog.Logger, c *config.Config, e chan StateEvent, tm pomodoroTracker, tt taskManager) *UIManager {
+func NewUIManager(l *slog.Logger, c *config.Config, e chan StateEvent, tm pomodoroTracker, tt taskManager,
+	events *EventBroker, keys *KeyBindings, theme *Theme) *UIManager {
 	stateChangeChan := make(chan StateEvent)
-	focusTimer := NewFocusTimer(c.Timer.FocusDuration)
-	breakTimer := NewBreakTimer(c.Timer.BreakDuration)
+	timerConfig := c.ActiveTimer()
+	focusTimer := NewFocusTimer(timerConfig.FocusDuration)
+	breakTimer := NewBreakTimer(timerConfig.BreakDuration)
 
 	m := &UIManager{
 		ui:                   tview.NewApplication(),
 		pages:                tview.NewPages(),
 		logger:               l,
-		stateManager:         NewStateManager(l, focusTimer, breakTimer, stateChangeChan, c.Timer, tt),
+		stateManager:         NewStateManager(l, focusTimer, breakTimer, stateChangeChan, timerConfig, tt),
 		stateUpdates:         stateChangeChan,
+		plans:                nil,
 		pomodoroTracker:      tm,
 		statePomodoroUpdates: e,
+		stateListeners:       nil,
 		allowedTransitions:   constructAllowedTransitions(),
 		taskTracker:          tt,
+		config:               c,
+		events:               events,
+		keys:                 keys,
+		theme:                theme,
 		keyPageMapping:       nil,
+		currentPage:          nil,
+		bottomPanel:          nil,
+		showKeyTips:          c.UI.ShowKeyTips,
 	}
+	m.stateManager.SetProfile(c.ActiveProfile(), timerConfig)
+	m.stateManager.SetFlowtime(c.Flowtime)
+	m.stateManager.SetMinimum(c.Minimum)
+	tm.SetPauseLimit(c.Pauses)
+	tm.SetDiscardShort(c.Minimum.Discard)
+	tm.OnSuspend(func(event SuspendEvent) {
+		m.ui.QueueUpdateDraw(func() {
+			m.showSuspendOffer(event)
+		})
+	})
+	m.plans = NewPlanRunner(m.stateManager)
 	m.keyPageMapping = m.constructKeyPageMap()
 
+	if theme.Monochrome {
+		screen, err := tcell.NewScreen()
+		if err != nil {
+			l.Error("can't create monochrome screen", slog.Any("error", err))
+		} else {
+			m.ui.SetScreen(newMonochromeScreen(screen))
+		}
+	}
+
 	return m
 }
 
@@ -82,5 +154,11 @@ func (m *UIManager) DefaultPage() {
 }
 
 func (m *UIManager) AddPageAndSwitch(page *Page) {
-	m.pages.AddAndSwitchToPage(string(page.name), page.WithBottomPanel(), page.resize)
+	m.closeHelp()
+	m.closeOverlay(plansPage)
+	m.currentPage = page
+	m.bottomPanel = m.constructBottomPanel(page.name)
+	m.pages.AddAndSwitchToPage(string(page.name),
+		page.WithBottomPanel(m.bottomPanel, m.constructKeyTips(page.name)), page.resize)
+	m.raiseSuspendOffer()
 }